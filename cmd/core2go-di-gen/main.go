@@ -0,0 +1,32 @@
+// Command core2go-di-gen generates typed accessors and registration code
+// for the di.Container dependencies defined by annotated constructors.
+//
+// Usage:
+//
+//	//go:generate go run github.com/kukymbr/core2go/cmd/core2go-di-gen
+//
+// See the digen package for the annotation format.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kukymbr/core2go/di/digen"
+)
+
+func main() {
+	cfg := digen.Config{}
+
+	flag.StringVar(&cfg.Dir, "dir", ".", "package directory to scan")
+	flag.StringVar(&cfg.Output, "output", digen.DefaultOutput, "generated file name")
+	flag.StringVar(&cfg.Register, "register", digen.DefaultRegister, "generated registration function name")
+	flag.Parse()
+
+	if err := digen.Generate(cfg); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "core2go-di-gen: "+err.Error())
+
+		os.Exit(1)
+	}
+}
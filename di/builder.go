@@ -2,41 +2,95 @@ package di
 
 import (
 	"fmt"
+	"sync"
 )
 
-// Builder is a Container builder
+// Builder is a Container builder.
+// Builder is safe for concurrent use, definitions of the single Add call are registered in a row.
 type Builder struct {
-	ctn *Container
-	ord []string
+	mu    sync.Mutex
+	ctn   *Container
+	built bool
 }
 
-// Add adds Def to the Container
+// Add adds Def to the Container.
+// Returns ErrBuilderBuilt if the Container is already built.
 func (b *Builder) Add(defs ...Def) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	b.initContainer()
 
+	if b.built {
+		return ErrBuilderBuilt
+	}
+
 	for _, def := range defs {
-		if _, exists := b.ctn.defs[def.Name]; exists {
+		if err := b.add(def, ConflictError); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// add adds Def to the Container resolving the name conflict with the given policy
+func (b *Builder) add(def Def, policy ConflictPolicy) error {
+	_, exists := b.ctn.defs[def.Name]
+	if exists {
+		switch policy {
+		case ConflictKeep:
+			return nil
+		case ConflictOverride:
+		default:
 			return fmt.Errorf("%s: %w", def.Name, ErrDefinitionExists)
 		}
+	}
 
-		if def.Validate != nil {
-			if err := def.Validate(b.ctn); err != nil {
-				return err
-			}
+	if def.Validate != nil {
+		if err := def.Validate(b.ctn); err != nil {
+			return err
 		}
+	}
 
-		b.ctn.defs[def.Name] = def
-		b.ord = append(b.ord, def.Name)
+	b.ctn.defs[def.Name] = def.reset()
+
+	if !exists {
+		b.ctn.ord = append(b.ctn.ord, def.Name)
 	}
 
+	b.ctn.getObservers().notify(Event{Type: EventDefinitionAdded, Name: def.Name})
+
 	return nil
 }
 
-// Build prepares Container and builds non-lazy definitions
+// AddObserver registers the Observer instances receiving the Container events
+func (b *Builder) AddObserver(obs ...Observer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.initContainer()
+	b.ctn.AddObserver(obs...)
+}
+
+// Build validates definitions, prepares Container and builds non-lazy definitions.
+// If validation failed, the *ValidationError is returned.
+// Once built, the same Container is returned on every call.
 func (b *Builder) Build() (*Container, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	b.initContainer()
 
-	for _, name := range b.ord {
+	if b.built {
+		return b.ctn, nil
+	}
+
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range b.ctn.ord {
 		def := b.ctn.defs[name]
 
 		if !def.Lazy {
@@ -48,10 +102,12 @@ func (b *Builder) Build() (*Container, error) {
 		}
 	}
 
+	b.built = true
+
 	return b.ctn, nil
 }
 
-// initContainer creates container instance
+// initContainer creates container instance, must be called under the Builder's lock
 func (b *Builder) initContainer() {
 	if b.ctn == nil {
 		b.ctn = &Container{
@@ -2,7 +2,10 @@ package di_test
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/kukymbr/core2go/di"
 	"github.com/stretchr/testify/assert"
@@ -271,3 +274,293 @@ func TestContainer_Lazy(t *testing.T) {
 		container.Get("testname2")
 	})
 }
+
+func TestContainer_Lazy_WhenCircular_ExpectError(t *testing.T) {
+	builder := &di.Builder{}
+
+	lazyGet := func(name string) func(ctn *di.Container) (any, error) {
+		return func(ctn *di.Container) (any, error) {
+			return ctn.SafeGet(name)
+		}
+	}
+
+	err := builder.Add(
+		di.Def{Name: "a", Build: lazyGet("b"), Lazy: true},
+		di.Def{Name: "b", Build: lazyGet("c"), Lazy: true},
+		di.Def{Name: "c", Build: lazyGet("a"), Lazy: true},
+	)
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := ctn.SafeGet("a")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, di.ErrCircularDependency)
+		assert.ErrorContains(t, err, "a -> b -> c -> a")
+	case <-time.After(time.Second):
+		t.Fatal("circular lazy build is deadlocked")
+	}
+
+	assert.Panics(t, func() {
+		ctn.Get("b")
+	})
+}
+
+func TestBuilder_Build_WhenValidationFailed_ExpectAggregatedError(t *testing.T) {
+	builder := &di.Builder{}
+
+	err := builder.Add(
+		di.Def{
+			Name:     "testname1",
+			Requires: []string{"testname2", "testname_unknown"},
+			Build: func(ctn *di.Container) (obj any, err error) {
+				return "testval1", nil
+			},
+		},
+		di.Def{Name: "testname2"},
+		di.Def{
+			Name: "testname3",
+			Build: func(ctn *di.Container) (obj any, err error) {
+				return "testval3", nil
+			},
+			Verify: func(ctn *di.Container) (err error) {
+				return errors.New("failed to verify")
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.Error(t, err)
+	assert.Nil(t, ctn)
+
+	var validationErr *di.ValidationError
+
+	require.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Errors, 3)
+	assert.ErrorIs(t, err, di.ErrBuildFunctionMissing)
+	assert.ErrorIs(t, err, di.ErrDependencyMissing)
+}
+
+func TestBuilder_Build_WhenRequiredAddedLater_ExpectNoError(t *testing.T) {
+	builder := &di.Builder{}
+
+	err := builder.Add(di.Def{
+		Name:     "testname1",
+		Requires: []string{"testname2"},
+		Build: func(ctn *di.Container) (obj any, err error) {
+			return ctn.Get("testname2").(string) + "_1", nil
+		},
+		Lazy: true,
+	})
+	require.NoError(t, err)
+
+	err = builder.Add(di.Def{
+		Name: "testname2",
+		Build: func(ctn *di.Container) (obj any, err error) {
+			return "testval2", nil
+		},
+		Verify: func(ctn *di.Container) (err error) {
+			if !ctn.Has("testname1") {
+				return errors.New("testname1 is expected")
+			}
+
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+	require.NotNil(t, ctn)
+}
+
+func TestBuilder_Clone_WhenBuilt_ExpectIndependentContainers(t *testing.T) {
+	type testItem struct {
+		name string
+	}
+
+	builder := &di.Builder{}
+
+	err := builder.Add(di.Def{
+		Name: "test",
+		Build: func(ctn *di.Container) (obj any, err error) {
+			return &testItem{name: "test"}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctn1, err := builder.Clone().Build()
+	require.NoError(t, err)
+
+	ctn2, err := builder.Clone().Build()
+	require.NoError(t, err)
+
+	assert.NotSame(t, ctn1, ctn2)
+	assert.NotSame(t, ctn1.Get("test"), ctn2.Get("test"))
+	assert.Equal(t, ctn1.Get("test"), ctn2.Get("test"))
+}
+
+func TestBuilder_Merge(t *testing.T) {
+	newDef := func(name string, val string) di.Def {
+		return di.Def{
+			Name: name,
+			Build: func(ctn *di.Container) (obj any, err error) {
+				return val, nil
+			},
+		}
+	}
+
+	tests := []struct {
+		Policy    di.ConflictPolicy
+		Expected  map[string]string
+		ExpectErr bool
+	}{
+		{
+			Policy:    di.ConflictError,
+			ExpectErr: true,
+		},
+		{
+			Policy:   di.ConflictKeep,
+			Expected: map[string]string{"testname1": "base1", "testname2": "base2", "testname3": "other3"},
+		},
+		{
+			Policy:   di.ConflictOverride,
+			Expected: map[string]string{"testname1": "base1", "testname2": "other2", "testname3": "other3"},
+		},
+	}
+
+	for i, test := range tests {
+		base := &di.Builder{}
+		other := &di.Builder{}
+
+		require.NoError(t, base.Add(newDef("testname1", "base1"), newDef("testname2", "base2")))
+		require.NoError(t, other.Add(newDef("testname2", "other2"), newDef("testname3", "other3")))
+
+		err := base.Merge(other, test.Policy)
+		if test.ExpectErr {
+			assert.ErrorIs(t, err, di.ErrDefinitionExists, i)
+
+			ctn, err := base.Build()
+			require.NoError(t, err, i)
+			assert.False(t, ctn.Has("testname3"), i)
+
+			continue
+		}
+
+		require.NoError(t, err, i)
+
+		ctn, err := base.Build()
+		require.NoError(t, err, i)
+		assert.Equal(t, len(test.Expected), ctn.Len(), i)
+
+		for name, expected := range test.Expected {
+			assert.Equal(t, expected, ctn.Get(name), i)
+		}
+	}
+}
+
+func TestBuilder_Add_WhenConcurrent_ExpectAllAdded(t *testing.T) {
+	builder := &di.Builder{}
+	recorder := &di.Recorder{}
+	wg := sync.WaitGroup{}
+
+	builder.AddObserver(recorder)
+
+	for i := 0; i < 10; i++ {
+		i := i
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			defs := make([]di.Def, 0, 10)
+
+			for j := 0; j < 10; j++ {
+				defs = append(defs, di.Def{
+					Name: fmt.Sprintf("testname_%d_%d", i, j),
+					Build: func(ctn *di.Container) (obj any, err error) {
+						return i, nil
+					},
+				})
+			}
+
+			assert.NoError(t, builder.Add(defs...))
+		}()
+	}
+
+	wg.Wait()
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, 100, ctn.Len())
+
+	events := recorder.Filter(di.EventBuildStart)
+	require.Len(t, events, 100)
+
+	for i := 0; i < len(events); i += 10 {
+		group := events[i].Name[:len(events[i].Name)-1]
+
+		for j := 0; j < 10; j++ {
+			assert.Equal(t, fmt.Sprintf("%s%d", group, j), events[i+j].Name)
+		}
+	}
+}
+
+func TestBuilder_Add_WhenBuilt_ExpectError(t *testing.T) {
+	builder := &di.Builder{}
+
+	_, err := builder.Build()
+	require.NoError(t, err)
+
+	err = builder.Add(di.Def{
+		Name: "testname1",
+		Build: func(ctn *di.Container) (obj any, err error) {
+			return "testval1", nil
+		},
+	})
+	assert.ErrorIs(t, err, di.ErrBuilderBuilt)
+
+	err = builder.Merge(&di.Builder{}, di.ConflictError)
+	assert.ErrorIs(t, err, di.ErrBuilderBuilt)
+}
+
+func TestContainer_Range(t *testing.T) {
+	builder := &di.Builder{}
+
+	for _, name := range []string{"testname1", "testname2", "testname3"} {
+		name := name
+
+		err := builder.Add(di.Def{
+			Name: name,
+			Build: func(ctn *di.Container) (obj any, err error) {
+				return name, nil
+			},
+			Lazy: name == "testname2",
+		})
+		require.NoError(t, err)
+	}
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	names := make([]string, 0)
+
+	ctn.Range(func(name string, obj any) bool {
+		names = append(names, name)
+		assert.Equal(t, name, obj)
+
+		return true
+	})
+
+	assert.Equal(t, []string{"testname1", "testname3"}, names)
+}
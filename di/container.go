@@ -3,17 +3,77 @@ package di
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Container is a dependency container
 type Container struct {
-	mu   sync.RWMutex
-	defs definitions
+	mu        sync.RWMutex
+	rmu       sync.Mutex
+	defs      definitions
+	ord       []string
+	locks     map[string]*sync.Mutex
+	observers observers
+
+	// origin is the Container the build scope is created from, see scope
+	origin *Container
+
+	// building are names of the lazy dependencies being built in the scope, outer first
+	building []string
+}
+
+// scope returns the Container passed to the lazy dependency build function.
+// It's a view of the origin Container tracking the chain of the lazy builds
+// to detect the circular dependencies.
+func (c *Container) scope(name string) *Container {
+	building := make([]string, 0, len(c.building)+1)
+	building = append(building, c.building...)
+
+	return &Container{origin: c.root(), building: append(building, name)}
+}
+
+// root returns the origin Container of the scope, or the Container itself
+func (c *Container) root() *Container {
+	if c.origin != nil {
+		return c.origin
+	}
+
+	return c
+}
+
+// AddObserver registers the Observer instances receiving the Container events
+func (c *Container) AddObserver(obs ...Observer) {
+	if c.origin != nil {
+		c.origin.AddObserver(obs...)
+
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Copy on write, so the snapshots returned by getObservers are not changed
+	c.observers = append(append(make(observers, 0, len(c.observers)+len(obs)), c.observers...), obs...)
+}
+
+// getObservers returns the snapshot of the observers registered in the origin Container
+func (c *Container) getObservers() observers {
+	base := c.root()
+
+	base.mu.RLock()
+	defer base.mu.RUnlock()
+
+	return base.observers
 }
 
 // Has checks if dependency is registered in Container
 func (c *Container) Has(name string) bool {
+	if c.origin != nil {
+		return c.origin.Has(name)
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -32,42 +92,163 @@ func (c *Container) Get(name string) (obj any) {
 	return obj
 }
 
-// SafeGet returns built dependency
+// SafeGet returns built dependency.
+// Returns ErrCircularDependency if the lazy dependency requests itself while being built,
+// ErrFactoryWithoutArgs if the dependency is a factory, use SafeGetWith for it.
 func (c *Container) SafeGet(name string) (obj any, err error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	base := c.root()
+
+	if base.isFactory(name) {
+		return nil, fmt.Errorf("%s: %w", name, ErrFactoryWithoutArgs)
+	}
+
+	base.mu.RLock()
+	def, ok := base.defs[name]
+	base.mu.RUnlock()
 
-	def, ok := c.defs[name]
 	if !ok {
 		return nil, fmt.Errorf("%s: %w", name, ErrDefinitionNotFound)
 	}
 
-	if def.Lazy {
-		err = def.build(c)
-		if err != nil {
-			return nil, err
+	base.getObservers().notify(Event{Type: EventResolve, Name: name})
+
+	if def.Lazy && !def.built {
+		return c.buildLazy(name)
+	}
+
+	return def.obj, nil
+}
+
+// buildLazy builds lazy dependency.
+// Container is not locked while building, so the build function can request other dependencies.
+func (c *Container) buildLazy(name string) (obj any, err error) {
+	for i, n := range c.building {
+		if n == name {
+			chain := strings.Join(append(c.building[i:], name), " -> ")
+
+			return nil, fmt.Errorf("%s: %w", chain, ErrCircularDependency)
 		}
+	}
+
+	base := c.root()
+	lock := base.buildLock(name)
 
-		c.defs[name] = def
+	lock.Lock()
+	defer lock.Unlock()
+
+	base.mu.RLock()
+	def := base.defs[name]
+	base.mu.RUnlock()
+
+	if def.built {
+		return def.obj, nil
 	}
 
+	if err := def.build(c.scope(name)); err != nil {
+		return nil, err
+	}
+
+	base.mu.Lock()
+	base.defs[name] = def
+	base.mu.Unlock()
+
 	return def.obj, nil
 }
 
+// buildLock returns the mutex preventing concurrent builds of the lazy dependency and its rebuild
+func (c *Container) buildLock(name string) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.locks == nil {
+		c.locks = make(map[string]*sync.Mutex)
+	}
+
+	lock, ok := c.locks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		c.locks[name] = lock
+	}
+
+	return lock
+}
+
+// Range calls fn for each built dependency in the registration order, until fn returns false.
+// Lazy dependencies not requested yet and factories are skipped.
+func (c *Container) Range(fn func(name string, obj any) bool) {
+	if c.origin != nil {
+		c.origin.Range(fn)
+
+		return
+	}
+
+	type item struct {
+		name string
+		obj  any
+	}
+
+	c.mu.RLock()
+
+	items := make([]item, 0, len(c.ord))
+
+	for _, name := range c.ord {
+		if def := c.defs[name]; def.built {
+			items = append(items, item{name: name, obj: def.obj})
+		}
+	}
+
+	c.mu.RUnlock()
+
+	for _, it := range items {
+		if !fn(it.name, it.obj) {
+			return
+		}
+	}
+}
+
 // Len returns count of definitions in the Container
 func (c *Container) Len() int {
+	if c.origin != nil {
+		return c.origin.Len()
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	return len(c.defs)
 }
 
-// Close finalizes dependencies
+// Close finalizes dependencies.
+// Observers are notified with the Container unlocked.
 func (c *Container) Close() (err error) {
+	if c.origin != nil {
+		return c.origin.Close()
+	}
+
+	obs := c.getObservers()
+	start := time.Now()
+
+	obs.notify(Event{Type: EventCloseStart})
+
+	err = c.closeDefs()
+
+	obs.notify(Event{Type: EventCloseFinish, Duration: time.Since(start), Err: err})
+
+	return err
+}
+
+// closeDefs closes the built dependencies and the cached factory instances
+func (c *Container) closeDefs() (err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	for _, def := range c.defs {
+		if def.instances != nil {
+			err = errors.Join(err, def.instances.close(def.Name, def.Close))
+
+			continue
+		}
+
 		if !def.built {
 			continue
 		}
@@ -3,6 +3,7 @@ package di
 import (
 	"fmt"
 	"runtime/debug"
+	"time"
 )
 
 // ValidateFn is a dependency validation function
@@ -11,6 +12,10 @@ type ValidateFn func(ctn *Container) (err error)
 // BuildFn is a dependency build function
 type BuildFn func(ctn *Container) (obj any, err error)
 
+// FactoryFn is a parameterized dependency build function,
+// receives arguments passed to the Container.GetWith
+type FactoryFn func(ctn *Container, args ...any) (obj any, err error)
+
 // CloseFn is a dependency close function
 type CloseFn func(obj any) (err error)
 
@@ -25,22 +30,56 @@ type Def struct {
 	// Build builds dependency object
 	Build BuildFn
 
+	// Factory builds dependency object using the Container.GetWith arguments.
+	// Factory is used instead of the Build function, only one of them must be set.
+	Factory FactoryFn
+
+	// Cache is a flag. If true, Factory objects are cached by arguments
+	// and closed with the Container. Arguments are compared with ==,
+	// so they must be comparable, and pointers are compared by the address.
+	Cache bool
+
 	// Validate validates dependency definition on add
 	Validate ValidateFn
 
+	// Verify validates dependency definition on Build, after all definitions are registered
+	Verify ValidateFn
+
+	// Requires is a list of the dependencies names, which must be registered in the Container.
+	// Checked on Build.
+	Requires []string
+
 	// Close finalizes dependency object
 	Close CloseFn
 
 	// Lazy is a flag. If true, Build will be executed only on Container.Get() call.
+	// Build of the lazy dependency receives the Container view tracking the lazy builds chain:
+	// requesting the dependency being built from its own build chain returns ErrCircularDependency.
 	Lazy bool
 
-	obj   any
-	built bool
+	obj       any
+	built     bool
+	duration  time.Duration
+	instances *instances
+}
+
+// reset returns copy of the definition without built objects
+func (d Def) reset() Def {
+	d.obj = nil
+	d.built = false
+	d.duration = 0
+	d.instances = nil
+
+	if d.Factory != nil {
+		d.instances = newInstances()
+	}
+
+	return d
 }
 
 // build builds dependency's object
 func (d *Def) build(ctn *Container) error {
-	if d.built {
+	if d.built || d.Factory != nil {
 		return nil
 	}
 
@@ -50,17 +89,24 @@ func (d *Def) build(ctn *Container) error {
 
 	var buildErr error
 
-	func() {
-		defer func() {
-			if r := recover(); r != nil {
-				stack := string(debug.Stack())
-				buildErr = fmt.Errorf("build panicked: %v; stack: %s", r, stack)
-			}
-		}()
+	start := time.Now()
 
-		d.obj, buildErr = d.Build(ctn)
+	obs := ctn.getObservers()
+
+	obs.notify(Event{Type: EventBuildStart, Name: d.Name})
+
+	defer func() {
+		d.duration = time.Since(start)
+
+		obs.notify(Event{Type: EventBuildFinish, Name: d.Name, Duration: d.duration, Err: buildErr})
 	}()
 
+	buildErr = recoverBuild(func() (err error) {
+		d.obj, err = d.Build(ctn)
+
+		return err
+	})
+
 	d.built = true
 
 	if buildErr != nil {
@@ -71,3 +117,15 @@ func (d *Def) build(ctn *Container) error {
 
 	return nil
 }
+
+// recoverBuild calls the build function and converts its panic to an error
+func recoverBuild(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := string(debug.Stack())
+			err = fmt.Errorf("build panicked: %v; stack: %s", r, stack)
+		}
+	}()
+
+	return fn()
+}
@@ -0,0 +1,16 @@
+// Command core2go-di-vet checks di.Container dependencies names and types.
+//
+// Usage:
+//
+//	go install github.com/kukymbr/core2go/di/dicheck/cmd/core2go-di-vet@latest
+//	go vet -vettool=$(which core2go-di-vet) ./...
+package main
+
+import (
+	"github.com/kukymbr/core2go/di/dicheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(dicheck.Analyzer)
+}
@@ -0,0 +1,456 @@
+// Package dicheck defines an Analyzer reporting misuse of the di.Container dependencies names and types.
+//
+// The Analyzer reports:
+//   - Container getters (Get, SafeGet, GetWith, SafeGetWith) and di.NewProvider calls
+//     with the names registered neither in the module nor in the package dependencies;
+//   - type assertions of the Get results and di.Provider type arguments,
+//     which can't be satisfied by the types returned from the Build functions;
+//   - definitions registered but never requested in the module or the package dependencies
+//     (disable with the -unused=false flag).
+//
+// Names are resolved from the string literals and the string constants.
+// The registered and used names and the Build results types are exported as the package facts,
+// the names of the module packages not imported by the analyzed one are collected from their sources,
+// so the definitions registered in the main package are visible in the leaf packages.
+// Types are checked against the definitions of the package and its dependencies only.
+// Packages with no definitions known are not checked for the unregistered names.
+package dicheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const doc = `check di.Container dependencies names and types
+
+Reports Container getters with the names not registered in the module, type assertions
+of the Get results, which can't be satisfied by the Build functions results,
+and registered but unused definitions (disable with the -unused=false flag).`
+
+// Analyzer is a di names and types checker
+//
+//nolint:gochecknoglobals
+var Analyzer = &analysis.Analyzer{
+	Name:      "dicheck",
+	Doc:       doc,
+	Run:       run,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(defsFact)},
+}
+
+//nolint:gochecknoglobals
+var reportUnused bool
+
+//nolint:gochecknoinits
+func init() {
+	Analyzer.Flags.BoolVar(&reportUnused, "unused", true, "report registered but unused definitions")
+}
+
+// checker is a single package check state
+type checker struct {
+	pass *analysis.Pass
+
+	// local are the names registered and used in the package
+	local *defsFact
+
+	// all are the names registered and used in the package and its dependencies
+	all *defsFact
+
+	// module are the names registered and used in the module
+	module *moduleDefs
+
+	// defs are positions of the names registered in the package
+	defs map[string]token.Pos
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	c := &checker{
+		pass:  pass,
+		local: newDefsFact(),
+		all:   newDefsFact(),
+		defs:  make(map[string]token.Pos),
+	}
+
+	if pass.Pkg.Path() == diPath {
+		return nil, nil //nolint:nilnil
+	}
+
+	insp, _ := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodes := []ast.Node{(*ast.CompositeLit)(nil), (*ast.CallExpr)(nil), (*ast.TypeAssertExpr)(nil)}
+
+	insp.Preorder(nodes, func(node ast.Node) {
+		switch n := node.(type) {
+		case *ast.CompositeLit:
+			c.collectDef(n)
+		case *ast.CallExpr:
+			c.collectCall(n)
+		}
+	})
+
+	if len(c.local.Registered) > 0 || len(c.local.Used) > 0 {
+		pass.ExportPackageFact(c.local)
+	}
+
+	c.all.merge(c.local)
+	c.module = moduleDefsOf(pass)
+
+	for _, fact := range pass.AllPackageFacts() {
+		if deps, ok := fact.Fact.(*defsFact); ok && fact.Package != pass.Pkg {
+			c.all.merge(deps)
+		}
+	}
+
+	insp.Preorder(nodes, func(node ast.Node) {
+		switch n := node.(type) {
+		case *ast.CallExpr:
+			c.checkCall(n)
+		case *ast.TypeAssertExpr:
+			c.checkAssert(n)
+		}
+	})
+
+	c.checkUnused()
+
+	return nil, nil //nolint:nilnil
+}
+
+// collectDef collects the name, the Build function result types and the requirements of the di.Def literal
+func (c *checker) collectDef(lit *ast.CompositeLit) {
+	if !isDiType(c.pass.TypesInfo.TypeOf(lit), "Def") {
+		return
+	}
+
+	var (
+		name    string
+		nameOk  bool
+		namePos token.Pos
+		build   *ast.FuncLit
+	)
+
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		key, _ := kv.Key.(*ast.Ident)
+
+		switch {
+		case key == nil:
+		case key.Name == "Name":
+			name, nameOk = c.constString(kv.Value)
+			namePos = kv.Value.Pos()
+		case key.Name == "Build":
+			build, _ = kv.Value.(*ast.FuncLit)
+		case key.Name == "Requires":
+			c.collectRequires(kv.Value)
+		}
+	}
+
+	if !nameOk {
+		return
+	}
+
+	refs := []typeRef{{Unknown: true}}
+	if build != nil {
+		refs = c.returnTypes(build)
+	}
+
+	c.register(name, namePos, refs...)
+}
+
+// collectRequires collects the names of the Def.Requires list
+func (c *checker) collectRequires(expr ast.Expr) {
+	list, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+
+	for _, item := range list.Elts {
+		if name, ok := c.constString(item); ok {
+			c.local.Used[name] = true
+		}
+	}
+}
+
+// collectCall collects the names of the getters, di.ProviderDef and di.NewProvider calls
+func (c *checker) collectCall(call *ast.CallExpr) {
+	if sel, ok := c.containerMethod(call); ok {
+		if name, ok := c.constString(call.Args[0]); ok && getterMethods[sel.Sel.Name] {
+			c.local.Used[name] = true
+		}
+
+		return
+	}
+
+	switch c.diFunc(call) {
+	case "ProviderDef":
+		if name, ok := c.constString(call.Args[0]); ok {
+			c.register(name, call.Args[0].Pos(), typeRef{Unknown: true})
+		}
+
+		if target, ok := c.constString(call.Args[1]); ok {
+			c.local.Used[target] = true
+		}
+	case "NewProvider":
+		if name, ok := c.constString(call.Args[1]); ok {
+			c.local.Used[name] = true
+		}
+	}
+}
+
+// register adds the name registered in the package
+func (c *checker) register(name string, pos token.Pos, refs ...typeRef) {
+	c.local.Registered[name] = append(c.local.Registered[name], refs...)
+
+	if _, ok := c.defs[name]; !ok {
+		c.defs[name] = pos
+	}
+}
+
+// checkUnused reports the definitions registered in the package and not used in the module or its dependencies
+func (c *checker) checkUnused() {
+	if !reportUnused {
+		return
+	}
+
+	for name, pos := range c.defs {
+		if !c.all.Used[name] && !c.module.Used[name] {
+			c.pass.Reportf(pos, "di definition %q is registered but never used", name)
+		}
+	}
+}
+
+// returnTypes returns types of the first results of the function's return statements
+func (c *checker) returnTypes(fn *ast.FuncLit) []typeRef {
+	var result []typeRef
+
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) != 2 {
+				return false
+			}
+
+			typ := c.pass.TypesInfo.TypeOf(n.Results[0])
+			if typ == nil || isNil(typ) {
+				return false
+			}
+
+			// Interfaces and composite types are unknown and satisfy any assertion
+			result = append(result, newTypeRef(typ))
+
+			return false
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// checkCall reports getters calls with the unregistered names
+// and di.NewProvider type arguments mismatch
+func (c *checker) checkCall(call *ast.CallExpr) {
+	if name, pos, ok := c.getterName(call); ok {
+		c.checkRegistered(name, pos)
+
+		return
+	}
+
+	if c.diFunc(call) != "NewProvider" {
+		return
+	}
+
+	name, ok := c.constString(call.Args[1])
+	if !ok {
+		return
+	}
+
+	c.checkRegistered(name, call.Args[1].Pos())
+
+	if typ := c.typeArg(call); typ != nil {
+		c.checkType(name, typ, call.Pos())
+	}
+}
+
+// checkAssert reports type assertions of the Get results, which can't be satisfied by the Build results
+func (c *checker) checkAssert(expr *ast.TypeAssertExpr) {
+	if expr.Type == nil {
+		return
+	}
+
+	call, ok := unparen(expr.X).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+
+	name, _, ok := c.getterName(call)
+	if !ok {
+		return
+	}
+
+	c.checkType(name, c.pass.TypesInfo.TypeOf(expr.Type), expr.Type.Pos())
+}
+
+// checkRegistered reports the name is registered neither in the module nor in the package dependencies.
+// Packages with no definitions known are not checked.
+func (c *checker) checkRegistered(name string, pos token.Pos) {
+	if _, ok := c.all.Registered[name]; ok {
+		return
+	}
+
+	if c.module.Registered[name] || (len(c.all.Registered) == 0 && len(c.module.Registered) == 0) {
+		return
+	}
+
+	c.pass.Reportf(pos, "di name %q is not registered in the module or its dependencies", name)
+}
+
+// checkType reports if none of the Build results of the name can satisfy the type
+func (c *checker) checkType(name string, typ types.Type, pos token.Pos) {
+	refs := c.all.Registered[name]
+	if len(refs) == 0 || typ == nil {
+		return
+	}
+
+	var first types.Type
+
+	for _, ref := range refs {
+		built := ref.resolve(c.pass.Pkg)
+		if built == nil || types.AssignableTo(built, typ) {
+			return
+		}
+
+		if first == nil {
+			first = built
+		}
+	}
+
+	c.pass.Reportf(pos, "di definition %q is built as %s, cannot be used as %s", name, first, typ)
+}
+
+// getterName returns the name argument of the Container's getter call
+func (c *checker) getterName(call *ast.CallExpr) (string, token.Pos, bool) {
+	sel, ok := c.containerMethod(call)
+	if !ok {
+		return "", token.NoPos, false
+	}
+
+	switch sel.Sel.Name {
+	case "Get", "SafeGet", "GetWith", "SafeGetWith":
+	default:
+		return "", token.NoPos, false
+	}
+
+	name, ok := c.constString(call.Args[0])
+
+	return name, call.Args[0].Pos(), ok
+}
+
+// containerMethod returns the selector of the Container's method call with arguments
+func (c *checker) containerMethod(call *ast.CallExpr) (*ast.SelectorExpr, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil, false
+	}
+
+	selection, ok := c.pass.TypesInfo.Selections[sel]
+	if !ok || !isDiType(selection.Recv(), "Container") {
+		return nil, false
+	}
+
+	return sel, true
+}
+
+// diFunc returns the name of the called two-arguments di package function
+func (c *checker) diFunc(call *ast.CallExpr) string {
+	fun := call.Fun
+
+	switch generic := fun.(type) {
+	case *ast.IndexExpr:
+		fun = generic.X
+	case *ast.IndexListExpr:
+		fun = generic.X
+	}
+
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) != 2 || !isDiObject(c.pass.TypesInfo.Uses[sel.Sel]) {
+		return ""
+	}
+
+	return sel.Sel.Name
+}
+
+// typeArg returns the type argument of the generic function call
+func (c *checker) typeArg(call *ast.CallExpr) types.Type {
+	fun := call.Fun
+	if generic, ok := fun.(*ast.IndexExpr); ok {
+		fun = generic.X
+	}
+
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	inst, ok := c.pass.TypesInfo.Instances[sel.Sel]
+	if !ok || inst.TypeArgs.Len() != 1 {
+		return nil
+	}
+
+	return inst.TypeArgs.At(0)
+}
+
+// constString returns the constant string value of the expression
+func (c *checker) constString(expr ast.Expr) (string, bool) {
+	tv, ok := c.pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(tv.Value), true
+}
+
+// isDiType checks if the type is the di package's named type (or a pointer to it)
+func isDiType(typ types.Type, name string) bool {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	named, ok := typ.(*types.Named)
+
+	return ok && named.Obj().Name() == name && isDiObject(named.Obj())
+}
+
+// isDiObject checks if the object is declared in the di package
+func isDiObject(obj types.Object) bool {
+	return obj != nil && obj.Pkg() != nil && obj.Pkg().Path() == diPath
+}
+
+// isNil checks if the type is an untyped nil
+func isNil(typ types.Type) bool {
+	basic, ok := typ.(*types.Basic)
+
+	return ok && basic.Kind() == types.UntypedNil
+}
+
+// unparen returns the expression with the enclosing parentheses removed
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+
+		expr = paren.X
+	}
+}
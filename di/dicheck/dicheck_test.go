@@ -0,0 +1,24 @@
+package dicheck_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kukymbr/core2go/di/dicheck"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+
+	analysistest.Run(t, testdata, dicheck.Analyzer, "./app/...")
+}
+
+func TestAnalyzer_WhenUnused_ExpectReported(t *testing.T) {
+	testdata, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+
+	analysistest.Run(t, testdata, dicheck.Analyzer, "./unused/...")
+}
@@ -0,0 +1,154 @@
+package dicheck
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+)
+
+const diPath = "github.com/kukymbr/core2go/di"
+
+// getterMethods are the Container methods receiving the dependency name as a first argument
+//
+//nolint:gochecknoglobals
+var getterMethods = map[string]bool{
+	"Get":         true,
+	"SafeGet":     true,
+	"GetWith":     true,
+	"SafeGetWith": true,
+	"Has":         true,
+	"Rebuild":     true,
+}
+
+// defsFact is a package fact with the di names registered and used in the package
+type defsFact struct {
+	// Registered are the Build functions result types by the registered names
+	Registered map[string][]typeRef
+
+	// Used are the names requested in the package
+	Used map[string]bool
+}
+
+// AFact implements the analysis.Fact interface.
+func (*defsFact) AFact() {}
+
+func (f *defsFact) String() string {
+	names := make([]string, 0, len(f.Registered))
+	for name := range f.Registered {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return fmt.Sprintf("defs%v", names)
+}
+
+func newDefsFact() *defsFact {
+	return &defsFact{
+		Registered: make(map[string][]typeRef),
+		Used:       make(map[string]bool),
+	}
+}
+
+// merge adds the registered and used names of the other fact
+func (f *defsFact) merge(other *defsFact) {
+	for name, refs := range other.Registered {
+		f.Registered[name] = append(f.Registered[name], refs...)
+	}
+
+	for name := range other.Used {
+		f.Used[name] = true
+	}
+}
+
+// typeRef is a portable reference to the Build function result type.
+// Only the named types (or pointers to them) and the predeclared types are referenced,
+// other types are unknown and satisfy any assertion.
+type typeRef struct {
+	Pkg     string
+	Name    string
+	Pointer bool
+	Unknown bool
+}
+
+// newTypeRef returns the reference to the type
+func newTypeRef(typ types.Type) typeRef {
+	ref := typeRef{}
+
+	if ptr, ok := typ.(*types.Pointer); ok {
+		ref.Pointer = true
+		typ = ptr.Elem()
+	}
+
+	switch t := typ.(type) {
+	case *types.Named:
+		if _, isInterface := t.Underlying().(*types.Interface); isInterface || t.TypeArgs().Len() > 0 {
+			return typeRef{Unknown: true}
+		}
+
+		ref.Name = t.Obj().Name()
+		if t.Obj().Pkg() != nil {
+			ref.Pkg = t.Obj().Pkg().Path()
+		}
+	case *types.Basic:
+		if t.Info()&types.IsUntyped != 0 {
+			typ = types.Default(t)
+		}
+
+		ref.Name = typ.String()
+	default:
+		return typeRef{Unknown: true}
+	}
+
+	return ref
+}
+
+// resolve returns the referenced type from the package or its imports, or nil if it's unknown
+func (ref typeRef) resolve(pkg *types.Package) types.Type {
+	if ref.Unknown {
+		return nil
+	}
+
+	scope := types.Universe
+
+	if ref.Pkg != "" {
+		found := findPackage(pkg, ref.Pkg, make(map[*types.Package]bool))
+		if found == nil {
+			return nil
+		}
+
+		scope = found.Scope()
+	}
+
+	obj, ok := scope.Lookup(ref.Name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+
+	if ref.Pointer {
+		return types.NewPointer(obj.Type())
+	}
+
+	return obj.Type()
+}
+
+// findPackage returns the package with the path among the package and its transitive imports
+func findPackage(pkg *types.Package, path string, visited map[*types.Package]bool) *types.Package {
+	if pkg.Path() == path {
+		return pkg
+	}
+
+	visited[pkg] = true
+
+	for _, imp := range pkg.Imports() {
+		if visited[imp] {
+			continue
+		}
+
+		if found := findPackage(imp, path, visited); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
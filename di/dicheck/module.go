@@ -0,0 +1,368 @@
+package dicheck
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/analysis"
+)
+
+// maxConstDepth limits the resolution of the constants declared with other constants
+const maxConstDepth = 8
+
+// moduleDefs are the di names registered and used anywhere in the module.
+// Collected syntactically, since the module packages not imported by the analyzed one
+// are not type checked and their facts are not available.
+type moduleDefs struct {
+	Registered map[string]bool
+	Used       map[string]bool
+}
+
+//nolint:gochecknoglobals
+var (
+	modulesMu sync.Mutex
+	modules   = make(map[string]*moduleDefs)
+)
+
+// moduleDefsOf returns the names of the module containing the package, empty if the module is not found
+func moduleDefsOf(pass *analysis.Pass) *moduleDefs {
+	if len(pass.Files) == 0 {
+		return &moduleDefs{}
+	}
+
+	root, modPath := findModule(filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name()))
+	if root == "" {
+		return &moduleDefs{}
+	}
+
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	if defs, ok := modules[root]; ok {
+		return defs
+	}
+
+	defs := scanModule(root, modPath)
+	modules[root] = defs
+
+	return defs
+}
+
+// findModule returns the root directory and the path of the module containing the directory
+func findModule(dir string) (root string, modPath string) {
+	for dir != "" {
+		if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, modfile.ModulePath(data)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+
+		dir = parent
+	}
+
+	return "", ""
+}
+
+// moduleScanner collects the names of the module packages
+type moduleScanner struct {
+	root    string
+	modPath string
+	fset    *token.FileSet
+
+	// files are the parsed files by the package directories
+	files map[string][]*ast.File
+
+	// consts are the constants declarations by the package directories
+	consts map[string]map[string]constDecl
+
+	defs *moduleDefs
+}
+
+// constDecl is a constant value expression and the declaring file
+type constDecl struct {
+	file  *ast.File
+	value ast.Expr
+}
+
+// scanModule collects the names of all module packages, except the testdata, vendor and nested modules
+func scanModule(root string, modPath string) *moduleDefs {
+	s := &moduleScanner{
+		root:    root,
+		modPath: modPath,
+		fset:    token.NewFileSet(),
+		files:   make(map[string][]*ast.File),
+		consts:  make(map[string]map[string]constDecl),
+		defs: &moduleDefs{
+			Registered: make(map[string]bool),
+			Used:       make(map[string]bool),
+		},
+	}
+
+	_ = filepath.WalkDir(root, s.parse)
+
+	for dir, files := range s.files {
+		for _, file := range files {
+			s.collect(dir, file)
+		}
+	}
+
+	return s.defs
+}
+
+// parse parses the module's go file, skipping the not module directories
+func (s *moduleScanner) parse(name string, entry fs.DirEntry, err error) error {
+	if err != nil {
+		return nil //nolint:nilerr
+	}
+
+	if entry.IsDir() {
+		base := entry.Name()
+		if name != s.root && (base == "testdata" || base == "vendor" ||
+			strings.HasPrefix(base, ".") || strings.HasPrefix(base, "_") || isModuleRoot(name)) {
+			return filepath.SkipDir
+		}
+
+		return nil
+	}
+
+	if !strings.HasSuffix(name, ".go") {
+		return nil
+	}
+
+	file, err := parser.ParseFile(s.fset, name, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil //nolint:nilerr
+	}
+
+	dir := filepath.Dir(name)
+	s.files[dir] = append(s.files[dir], file)
+
+	if s.consts[dir] == nil {
+		s.consts[dir] = make(map[string]constDecl)
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			value, _ := spec.(*ast.ValueSpec)
+			if value == nil || len(value.Names) != len(value.Values) {
+				continue
+			}
+
+			for i, ident := range value.Names {
+				s.consts[dir][ident.Name] = constDecl{file: file, value: value.Values[i]}
+			}
+		}
+	}
+
+	return nil
+}
+
+// collect collects the registered and used names of the file
+func (s *moduleScanner) collect(dir string, file *ast.File) {
+	diName := diImportName(file)
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.CompositeLit:
+			if isSelector(n.Type, diName, "Def") {
+				s.collectDef(dir, file, n)
+			}
+		case *ast.CallExpr:
+			s.collectCall(dir, file, diName, n)
+		}
+
+		return true
+	})
+}
+
+// collectDef collects the name and the requirements of the di.Def literal
+func (s *moduleScanner) collectDef(dir string, file *ast.File, lit *ast.CompositeLit) {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		key, _ := kv.Key.(*ast.Ident)
+
+		switch {
+		case key == nil:
+		case key.Name == "Name":
+			if name, ok := s.constString(dir, file, kv.Value, 0); ok {
+				s.defs.Registered[name] = true
+			}
+		case key.Name == "Requires":
+			list, _ := kv.Value.(*ast.CompositeLit)
+			if list == nil {
+				continue
+			}
+
+			for _, item := range list.Elts {
+				if name, ok := s.constString(dir, file, item, 0); ok {
+					s.defs.Used[name] = true
+				}
+			}
+		}
+	}
+}
+
+// collectCall collects the names of the getters, di.ProviderDef and di.NewProvider calls.
+// Getters are matched by the method names only, so the used names are overestimated.
+func (s *moduleScanner) collectCall(dir string, file *ast.File, diName string, call *ast.CallExpr) {
+	fun := call.Fun
+
+	switch generic := fun.(type) {
+	case *ast.IndexExpr:
+		fun = generic.X
+	case *ast.IndexListExpr:
+		fun = generic.X
+	}
+
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) == 0 {
+		return
+	}
+
+	switch {
+	case getterMethods[sel.Sel.Name]:
+		if name, ok := s.constString(dir, file, call.Args[0], 0); ok {
+			s.defs.Used[name] = true
+		}
+	case len(call.Args) == 2 && isSelector(sel, diName, "ProviderDef"):
+		if name, ok := s.constString(dir, file, call.Args[0], 0); ok {
+			s.defs.Registered[name] = true
+		}
+
+		if target, ok := s.constString(dir, file, call.Args[1], 0); ok {
+			s.defs.Used[target] = true
+		}
+	case len(call.Args) == 2 && isSelector(sel, diName, "NewProvider"):
+		if name, ok := s.constString(dir, file, call.Args[1], 0); ok {
+			s.defs.Used[name] = true
+		}
+	}
+}
+
+// constString returns the value of the string literal or the string constant
+// declared in the package or in the other module package
+func (s *moduleScanner) constString(dir string, file *ast.File, expr ast.Expr, depth int) (string, bool) {
+	if depth > maxConstDepth {
+		return "", false
+	}
+
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+
+		value, err := strconv.Unquote(e.Value)
+
+		return value, err == nil
+	case *ast.ParenExpr:
+		return s.constString(dir, file, e.X, depth+1)
+	case *ast.Ident:
+		decl, ok := s.consts[dir][e.Name]
+		if !ok {
+			return "", false
+		}
+
+		return s.constString(dir, decl.file, decl.value, depth+1)
+	case *ast.SelectorExpr:
+		pkg, ok := e.X.(*ast.Ident)
+		if !ok {
+			return "", false
+		}
+
+		pkgDir, ok := s.importDir(file, pkg.Name)
+		if !ok {
+			return "", false
+		}
+
+		decl, ok := s.consts[pkgDir][e.Sel.Name]
+		if !ok {
+			return "", false
+		}
+
+		return s.constString(pkgDir, decl.file, decl.value, depth+1)
+	}
+
+	return "", false
+}
+
+// importDir returns the directory of the module package imported by the file with the name
+func (s *moduleScanner) importDir(file *ast.File, name string) (string, bool) {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || importName(spec, importPath) != name {
+			continue
+		}
+
+		if importPath != s.modPath && !strings.HasPrefix(importPath, s.modPath+"/") {
+			return "", false
+		}
+
+		rel := strings.TrimPrefix(strings.TrimPrefix(importPath, s.modPath), "/")
+
+		return filepath.Join(s.root, filepath.FromSlash(rel)), true
+	}
+
+	return "", false
+}
+
+// diImportName returns the name of the di package in the file, or empty string if it's not imported
+func diImportName(file *ast.File) string {
+	for _, spec := range file.Imports {
+		if importPath, err := strconv.Unquote(spec.Path.Value); err == nil && importPath == diPath {
+			return importName(spec, importPath)
+		}
+	}
+
+	return ""
+}
+
+// importName returns the name of the imported package in the file.
+// The package name is assumed to match the last import path element.
+func importName(spec *ast.ImportSpec, importPath string) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+
+	return path.Base(importPath)
+}
+
+// isSelector checks if the expression selects the name from the package
+func isSelector(expr ast.Expr, pkg string, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || pkg == "" || sel.Sel.Name != name {
+		return false
+	}
+
+	ident, ok := sel.X.(*ast.Ident)
+
+	return ok && ident.Name == pkg
+}
+
+// isModuleRoot checks if the directory contains the go.mod file
+func isModuleRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "go.mod"))
+
+	return err == nil
+}
@@ -0,0 +1,64 @@
+package app // want package:"defs\\[app_cache app_config user_repo user_service user_service_provider\\]"
+
+import (
+	"errors"
+
+	"example.com/app/repo"
+	"github.com/kukymbr/core2go/di"
+)
+
+const DefCache = "app_cache"
+
+type UserService struct {
+	Repo repo.UserRepo
+}
+
+func Register(builder *di.Builder) error {
+	return builder.Add(
+		di.Def{
+			Name: repo.DefUserRepo,
+			Build: func(ctn *di.Container) (any, error) {
+				if ctn == nil {
+					return nil, errors.New("no container")
+				}
+
+				return &repo.SQLUserRepo{}, nil
+			},
+		},
+		di.Def{
+			Name:     "user_service",
+			Requires: []string{repo.DefUserRepo},
+			Build: func(ctn *di.Container) (any, error) {
+				return &UserService{Repo: ctn.Get(repo.DefUserRepo).(repo.UserRepo)}, nil
+			},
+		},
+		di.Def{
+			Name: DefCache,
+			Build: func(ctn *di.Container) (any, error) {
+				return ctn.Get(repo.DefCache).(*repo.Cache), nil
+			},
+		},
+		di.Def{
+			Name: "app_config",
+			Build: func(ctn *di.Container) (any, error) {
+				return "config", nil
+			},
+		},
+		di.ProviderDef[*UserService]("user_service_provider", "user_service"),
+	)
+}
+
+func Use(ctn *di.Container) {
+	_ = ctn.Get("user_service").(*UserService)
+	_ = ctn.Get("user_service").(string) // want `di definition "user_service" is built as \*example.com/app.UserService, cannot be used as string`
+	_ = (ctn.Get(repo.DefUserRepo)).(*repo.SQLUserRepo)
+	_ = ctn.Get("user_servce") // want `di name "user_servce" is not registered in the module or its dependencies`
+	_ = ctn.Get(DefCache).(*repo.Cache)
+	_ = ctn.Get(repo.DefCache).(*UserService) // want `di definition "repo_cache" is built as \*example.com/app/repo.Cache, cannot be used as \*example.com/app.UserService`
+	_ = ctn.Get("app_config").(int)           // want `di definition "app_config" is built as string, cannot be used as int`
+	_ = ctn.Get("user_service_provider").(*di.Provider[*UserService])
+
+	_ = di.NewProvider[*UserService](ctn, "user_service")
+	_ = di.NewProvider[int](ctn, "user_service") // want `di definition "user_service" is built as \*example.com/app.UserService, cannot be used as int`
+	_ = di.NewProvider[int](ctn, "unknown")      // want `di name "unknown" is not registered in the module or its dependencies`
+}
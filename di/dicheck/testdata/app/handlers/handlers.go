@@ -0,0 +1,14 @@
+package handlers // want package:"defs\\[\\]"
+
+import (
+	"example.com/app/repo"
+	"github.com/kukymbr/core2go/di"
+)
+
+// Handle uses the definitions registered in the app package, which is not imported here
+func Handle(ctn *di.Container) {
+	_ = ctn.Get("user_service")
+	_ = ctn.Get(repo.DefCache).(*repo.Cache)
+	_ = di.NewProvider[any](ctn, "user_service_provider")
+	_ = ctn.Get("user_servce") // want `di name "user_servce" is not registered in the module or its dependencies`
+}
@@ -0,0 +1,29 @@
+package repo // want package:"defs\\[repo_cache\\]"
+
+import "github.com/kukymbr/core2go/di"
+
+const DefUserRepo = "user_repo"
+
+const DefCache = "repo_cache" // Same constant name is declared in the app package
+
+type UserRepo interface {
+	Find(id int) string
+}
+
+type SQLUserRepo struct{}
+
+func (r *SQLUserRepo) Find(_ int) string {
+	return ""
+}
+
+type Cache struct{}
+
+func Register(builder *di.Builder) error {
+	return builder.Add(di.Def{
+		Name:     DefCache,
+		Requires: []string{"app_config"}, // Registered in the app package, not visible here
+		Build: func(ctn *di.Container) (any, error) {
+			return &Cache{}, nil
+		},
+	})
+}
@@ -0,0 +1,13 @@
+module example.com
+
+go 1.20
+
+require github.com/kukymbr/core2go v0.0.0
+
+require (
+	go.uber.org/atomic v1.11.0 // indirect
+	go.uber.org/multierr v1.11.0 // indirect
+	go.uber.org/zap v1.24.0 // indirect
+)
+
+replace github.com/kukymbr/core2go => ../../..
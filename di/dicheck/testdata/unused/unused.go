@@ -0,0 +1,33 @@
+package unused // want package:"defs\\[app_config orphan used\\]"
+
+import (
+	"example.com/app/repo"
+	"github.com/kukymbr/core2go/di"
+)
+
+func Register(builder *di.Builder) error {
+	return builder.Add(
+		di.Def{
+			Name: "orphan", // want `di definition "orphan" is registered but never used`
+			Build: func(ctn *di.Container) (any, error) {
+				return 42, nil
+			},
+		},
+		di.Def{
+			Name: "used",
+			Build: func(ctn *di.Container) (any, error) {
+				return ctn.Get(repo.DefCache), nil
+			},
+		},
+		di.Def{
+			Name: "app_config", // Required in the repo package
+			Build: func(ctn *di.Container) (any, error) {
+				return "config", nil
+			},
+		},
+	)
+}
+
+func Use(ctn *di.Container) {
+	_ = ctn.Get("used")
+}
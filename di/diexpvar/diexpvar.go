@@ -0,0 +1,49 @@
+// Package diexpvar publishes the di.Container and service.Service states with the expvar package.
+//
+// Importing the expvar package registers the /debug/vars handler on the http.DefaultServeMux,
+// exposing the process command line and memory stats, so the publishing is moved
+// to this opt-in package: the di and service packages provide the Vars methods only.
+package diexpvar
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+)
+
+// ErrVarExists is returned if the variable with the name is already published
+var ErrVarExists = errors.New("expvar variable is already published")
+
+// VarsSource is a source of the published state, like the di.Container or the service.Service
+type VarsSource[T any] interface {
+	Vars() T
+}
+
+// Publish publishes the source state (see di.Container.Vars and service.Service.Vars)
+// with the expvar package under the given name.
+// Returns ErrVarExists if the variable with the name is already published.
+func Publish[T any](name string, src VarsSource[T]) error {
+	return PublishFunc(name, func() any {
+		return src.Vars()
+	})
+}
+
+//nolint:gochecknoglobals
+var publishMu sync.Mutex
+
+// PublishFunc publishes the function result with the expvar package under the given name.
+// Returns ErrVarExists if the variable with the name is already published.
+func PublishFunc(name string, fn func() any) error {
+	// expvar.Publish panics on duplicates, so check first
+	publishMu.Lock()
+	defer publishMu.Unlock()
+
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("%s: %w", name, ErrVarExists)
+	}
+
+	expvar.Publish(name, expvar.Func(fn))
+
+	return nil
+}
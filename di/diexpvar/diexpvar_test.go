@@ -0,0 +1,61 @@
+package diexpvar_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/di/diexpvar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:gochecknoglobals
+var seq atomic.Int64
+
+// varName returns the name unique for the test run, since expvar variables can't be unpublished
+func varName(t *testing.T) string {
+	return fmt.Sprintf("%s_%d", t.Name(), seq.Add(1))
+}
+
+func TestPublish(t *testing.T) {
+	builder := &di.Builder{}
+
+	err := builder.Add(di.Def{
+		Name: "eager",
+		Build: func(ctn *di.Container) (obj any, err error) {
+			return "eager", nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	name := varName(t)
+
+	err = diexpvar.Publish[di.ContainerVars](name, ctn)
+	require.NoError(t, err)
+
+	vars := di.ContainerVars{}
+
+	err = json.Unmarshal([]byte(expvar.Get(name).String()), &vars)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, vars.Built)
+	assert.True(t, vars.Definitions["eager"].Built)
+}
+
+func TestPublishFunc_WhenDuplicate_ExpectError(t *testing.T) {
+	name := varName(t)
+
+	err := diexpvar.PublishFunc(name, func() any { return 1 })
+	require.NoError(t, err)
+
+	err = diexpvar.PublishFunc(name, func() any { return 2 })
+	assert.ErrorIs(t, err, diexpvar.ErrVarExists)
+	assert.Equal(t, "1", expvar.Get(name).String())
+}
@@ -0,0 +1,72 @@
+// Package digen generates typed accessors and registration code
+// for the di.Container dependencies defined by annotated constructors.
+//
+// Constructor annotation format:
+//
+//	//di:def <name> [lazy] [close] [requires=<name1>,<name2>]
+//
+// Supported constructor signatures:
+//
+//	func NewT(ctn *di.Container) (T, error)
+//	func NewT(ctn *di.Container) T
+//	func NewT() (T, error)
+//	func NewT() T
+package digen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Default generator options
+const (
+	DefaultOutput   = "di_gen.go"
+	DefaultRegister = "RegisterDefinitions"
+)
+
+// Config is a generator config
+type Config struct {
+	// Dir is a package directory to scan
+	Dir string
+
+	// Output is a name of the generated file, created in the Dir
+	Output string
+
+	// Register is a name of the generated registration function
+	Register string
+}
+
+// Generate scans the package for the annotated constructors and writes the generated file
+func Generate(cfg Config) error {
+	if cfg.Dir == "" {
+		cfg.Dir = "."
+	}
+
+	if cfg.Output == "" {
+		cfg.Output = DefaultOutput
+	}
+
+	if cfg.Register == "" {
+		cfg.Register = DefaultRegister
+	}
+
+	pkg, err := Parse(cfg.Dir, cfg.Output)
+	if err != nil {
+		return err
+	}
+
+	code, err := Render(pkg, cfg.Register)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(cfg.Dir, cfg.Output)
+
+	//nolint:gosec
+	if err := os.WriteFile(path, code, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package digen_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kukymbr/core2go/di/digen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_WhenValid_ExpectGolden(t *testing.T) {
+	pkg, err := digen.Parse("testdata/app", digen.DefaultOutput)
+	require.NoError(t, err)
+
+	assert.Equal(t, "app", pkg.Name)
+	require.Len(t, pkg.Definitions, 3)
+	assert.Equal(t, "strings", pkg.Imports["strings"])
+
+	repo := pkg.Definitions[1]
+	assert.Equal(t, "user_repo", repo.Name)
+	assert.Equal(t, "NewUserRepo", repo.Constructor)
+	assert.Equal(t, "*UserRepo", repo.Type)
+	assert.True(t, repo.WithContainer)
+	assert.True(t, repo.WithError)
+	assert.True(t, repo.Close)
+	assert.False(t, repo.Lazy)
+	assert.Equal(t, []string{"strings_builder"}, repo.Requires)
+
+	code, err := digen.Render(pkg, digen.DefaultRegister)
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile(filepath.Join("testdata/app", digen.DefaultOutput))
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(code))
+}
+
+func TestGenerate_WhenInvalid_ExpectError(t *testing.T) {
+	tests := []string{
+		"//di:def\nfunc NewT() int { return 1 }",
+		"//di:def t unknown_option\nfunc NewT() int { return 1 }",
+		"//di:def t\nfunc NewT(s string) int { return 1 }",
+		"//di:def t\nfunc NewT() {}",
+		"//di:def t\nfunc NewT() (int, int) { return 1, 1 }",
+		"//di:def t\nfunc NewT() int { return 1 }\n\n//di:def t\nfunc NewT2() int { return 1 }",
+		"//di:def -\nfunc NewT() int { return 1 }",
+	}
+
+	for i, test := range tests {
+		dir := t.TempDir()
+
+		err := os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n\n"+test+"\n"), 0o600)
+		require.NoError(t, err)
+
+		err = digen.Generate(digen.Config{Dir: dir})
+		assert.Error(t, err, i)
+
+		_, err = os.Stat(filepath.Join(dir, digen.DefaultOutput))
+		assert.ErrorIs(t, err, os.ErrNotExist, i)
+	}
+}
+
+func TestGenerate_WhenIdentifiersCollide_ExpectError(t *testing.T) {
+	dir := t.TempDir()
+	src := "package app\n\n//di:def user-repo\nfunc NewA() int { return 1 }\n\n//di:def user_repo\nfunc NewB() int { return 1 }\n"
+
+	err := os.WriteFile(filepath.Join(dir, "app.go"), []byte(src), 0o600)
+	require.NoError(t, err)
+
+	err = digen.Generate(digen.Config{Dir: dir})
+
+	assert.ErrorIs(t, err, digen.ErrNameCollision)
+	assert.ErrorContains(t, err, `"user-repo" and "user_repo" are both generated as UserRepo`)
+}
+
+func TestGenerate_WhenValid_ExpectFileWritten(t *testing.T) {
+	dir := t.TempDir()
+	src := "package app\n\n//di:def my.value lazy\nfunc NewValue() (int, error) { return 1, nil }\n"
+
+	err := os.WriteFile(filepath.Join(dir, "app.go"), []byte(src), 0o600)
+	require.NoError(t, err)
+
+	err = digen.Generate(digen.Config{Dir: dir, Output: "gen.go", Register: "Register"})
+	require.NoError(t, err)
+
+	code, err := os.ReadFile(filepath.Join(dir, "gen.go"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(code), "func GetMyValue(ctn *di.Container) int {")
+	assert.Contains(t, string(code), "func Register(builder *di.Builder) error {")
+	assert.Contains(t, string(code), "Lazy: true,")
+}
@@ -0,0 +1,393 @@
+package digen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	annotation = "di:def"
+	diPath     = "github.com/kukymbr/core2go/di"
+)
+
+// Parser errors
+var (
+	ErrNoPackage         = errors.New("no go files found")
+	ErrInvalidAnnotation = errors.New("invalid di:def annotation")
+	ErrInvalidSignature  = errors.New("unsupported constructor signature")
+	ErrDuplicateName     = errors.New("duplicate definition name")
+	ErrNameCollision     = errors.New("definition names map to the same identifier")
+)
+
+// Package is a scanned package
+type Package struct {
+	// Name is a package name
+	Name string
+
+	// Imports is a map of the import paths used by the definitions' types to their names
+	Imports map[string]string
+
+	// Definitions is a list of the annotated constructors, sorted by name
+	Definitions []Definition
+}
+
+// Definition is an annotated constructor
+type Definition struct {
+	// Name is a dependency name
+	Name string
+
+	// Constructor is a constructor function name
+	Constructor string
+
+	// Type is a constructor result type expression
+	Type string
+
+	// WithContainer is true if constructor receives the *di.Container
+	WithContainer bool
+
+	// WithError is true if constructor returns an error
+	WithError bool
+
+	// Lazy is a di.Def.Lazy flag value
+	Lazy bool
+
+	// Close is true if dependency should be closed with its Close() error method
+	Close bool
+
+	// Requires is a di.Def.Requires value
+	Requires []string
+}
+
+// Parse scans the package directory for the annotated constructors.
+// Test files and files with names from the skip list are ignored.
+func Parse(dir string, skip ...string) (*Package, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read dir %s: %w", dir, err)
+	}
+
+	skipped := make(map[string]bool, len(skip))
+	for _, name := range skip {
+		skipped[name] = true
+	}
+
+	pkg := &Package{
+		Imports: make(map[string]string),
+	}
+	fset := token.NewFileSet()
+	names := make(map[string]string)
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !isSourceFile(entry, skipped) {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+
+		pkg.Name = file.Name.Name
+
+		if err := parseFile(fset, file, pkg, names); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	if pkg.Name == "" {
+		return nil, fmt.Errorf("%s: %w", dir, ErrNoPackage)
+	}
+
+	sort.Slice(pkg.Definitions, func(i, j int) bool {
+		return pkg.Definitions[i].Name < pkg.Definitions[j].Name
+	})
+
+	return pkg, nil
+}
+
+// isSourceFile checks if the directory entry is a not skipped go source file
+func isSourceFile(entry os.DirEntry, skipped map[string]bool) bool {
+	name := entry.Name()
+
+	return !entry.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") && !skipped[name]
+}
+
+// parseFile adds file's annotated constructors to the package.
+// Names are the definitions names by their identifiers, see identifier.
+func parseFile(fset *token.FileSet, file *ast.File, pkg *Package, names map[string]string) error {
+	imports := fileImports(file)
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Doc == nil {
+			continue
+		}
+
+		args, ok := findAnnotation(fn.Doc)
+		if !ok {
+			continue
+		}
+
+		if err := parseConstructor(fset, fn, args, imports, pkg, names); err != nil {
+			return fmt.Errorf("%s: %w", fn.Name.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// parseConstructor adds the annotated constructor to the package
+func parseConstructor(
+	fset *token.FileSet,
+	fn *ast.FuncDecl,
+	args []string,
+	imports map[string]string,
+	pkg *Package,
+	names map[string]string,
+) error {
+	def, err := parseAnnotation(args)
+	if err != nil {
+		return err
+	}
+
+	if err := checkName(def.Name, names); err != nil {
+		return err
+	}
+
+	def.Constructor = fn.Name.Name
+
+	typ, err := parseSignature(fn.Type, imports, &def)
+	if err != nil {
+		return err
+	}
+
+	def.Type, err = exprString(fset, typ)
+	if err != nil {
+		return err
+	}
+
+	for _, pkgName := range typePackages(typ) {
+		importPath, ok := imports[pkgName]
+		if !ok {
+			return fmt.Errorf("unknown package %s", pkgName)
+		}
+
+		pkg.Imports[importPath] = pkgName
+	}
+
+	pkg.Definitions = append(pkg.Definitions, def)
+
+	return nil
+}
+
+// checkName checks the definition name is unique and its identifier doesn't collide with the other one,
+// adds it to the names by identifiers
+func checkName(name string, names map[string]string) error {
+	id := identifier(name)
+
+	if id == "" {
+		return fmt.Errorf("%w: name %q has no letters or digits", ErrInvalidAnnotation, name)
+	}
+
+	existing, ok := names[id]
+
+	switch {
+	case !ok:
+		names[id] = name
+
+		return nil
+	case existing == name:
+		return fmt.Errorf("%w: %s", ErrDuplicateName, name)
+	default:
+		return fmt.Errorf("%w: %q and %q are both generated as %s", ErrNameCollision, existing, name, id)
+	}
+}
+
+// findAnnotation returns the annotation arguments from the doc comment
+func findAnnotation(doc *ast.CommentGroup) ([]string, bool) {
+	for _, comment := range doc.List {
+		text := strings.TrimPrefix(comment.Text, "//")
+		if !strings.HasPrefix(text, annotation) {
+			continue
+		}
+
+		return strings.Fields(strings.TrimPrefix(text, annotation)), true
+	}
+
+	return nil, false
+}
+
+// parseAnnotation parses the annotation arguments
+func parseAnnotation(args []string) (Definition, error) {
+	def := Definition{}
+
+	if len(args) == 0 || strings.Contains(args[0], "=") {
+		return def, fmt.Errorf("%w: name is missing", ErrInvalidAnnotation)
+	}
+
+	def.Name = args[0]
+
+	for _, arg := range args[1:] {
+		switch {
+		case arg == "lazy":
+			def.Lazy = true
+		case arg == "close":
+			def.Close = true
+		case strings.HasPrefix(arg, "requires="):
+			def.Requires = strings.Split(strings.TrimPrefix(arg, "requires="), ",")
+		default:
+			return def, fmt.Errorf("%w: unknown option %s", ErrInvalidAnnotation, arg)
+		}
+	}
+
+	return def, nil
+}
+
+// parseSignature checks the constructor signature and returns its result type
+func parseSignature(fn *ast.FuncType, imports map[string]string, def *Definition) (ast.Expr, error) {
+	params := fn.Params.List
+
+	switch {
+	case len(params) == 0:
+	case len(params) == 1 && len(params[0].Names) <= 1 && isContainer(params[0].Type, imports):
+		def.WithContainer = true
+	default:
+		return nil, fmt.Errorf("%w: expected no params or *di.Container", ErrInvalidSignature)
+	}
+
+	return parseResults(fn, def)
+}
+
+// parseResults checks the constructor results and returns its result type
+func parseResults(fn *ast.FuncType, def *Definition) (ast.Expr, error) {
+	if fn.Results == nil {
+		return nil, fmt.Errorf("%w: no results", ErrInvalidSignature)
+	}
+
+	results := make([]ast.Expr, 0, 2)
+
+	for _, field := range fn.Results.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+
+		for i := 0; i < count; i++ {
+			results = append(results, field.Type)
+		}
+	}
+
+	switch {
+	case len(results) == 1:
+	case len(results) == 2 && isIdent(results[1], "error"):
+		def.WithError = true
+	default:
+		return nil, fmt.Errorf("%w: expected T or (T, error) results", ErrInvalidSignature)
+	}
+
+	return results[0], nil
+}
+
+// isContainer checks if the expression is a *di.Container type
+func isContainer(expr ast.Expr, imports map[string]string) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Container" {
+		return false
+	}
+
+	pkgIdent, ok := sel.X.(*ast.Ident)
+
+	return ok && imports[pkgIdent.Name] == diPath
+}
+
+// isIdent checks if the expression is an identifier with the given name
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+
+	return ok && ident.Name == name
+}
+
+// fileImports returns a map of the file's import names to their paths
+func fileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string, len(file.Imports))
+
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := importName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		imports[name] = importPath
+	}
+
+	return imports
+}
+
+// importName guesses the package name by its import path
+func importName(importPath string) string {
+	name := path.Base(importPath)
+
+	if strings.HasPrefix(name, "v") && len(name) > 1 {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			name = path.Base(path.Dir(importPath))
+		}
+	}
+
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, ".go")
+
+	return strings.ReplaceAll(name, "-", "")
+}
+
+// typePackages returns names of the packages used in the type expression
+func typePackages(expr ast.Expr) []string {
+	var names []string
+
+	ast.Inspect(expr, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			names = append(names, ident.Name)
+		}
+
+		return false
+	})
+
+	return names
+}
+
+// exprString returns the expression source code
+func exprString(fset *token.FileSet, expr ast.Expr) (string, error) {
+	buf := &bytes.Buffer{}
+
+	if err := printer.Fprint(buf, fset, expr); err != nil {
+		return "", fmt.Errorf("print type: %w", err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,191 @@
+package digen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const tplSource = `// Code generated by core2go-di-gen. DO NOT EDIT.
+
+package {{ .Name }}
+
+import (
+{{- range .StdImports }}
+	{{ . }}
+{{- end }}
+{{ if .StdImports }}
+{{ end -}}
+{{- range .Imports }}
+	{{ . }}
+{{- end }}
+)
+
+{{ if .Definitions -}}
+// Dependencies names.
+const (
+{{- range .Definitions }}
+	{{ .Const }} = {{ .Quoted }}
+{{- end }}
+)
+{{- end }}
+{{ range .Definitions }}
+// {{ .Getter }} returns the {{ .Quoted }} dependency. Panics on error.
+func {{ .Getter }}(ctn *di.Container) {{ .Type }} {
+	return ctn.Get({{ .Const }}).({{ .Type }})
+}
+{{ end }}
+// {{ .Register }} adds the generated definitions to the di.Builder.
+func {{ .Register }}(builder *di.Builder) error {
+	return builder.Add(
+{{- range .Definitions }}
+		di.Def{
+			Name: {{ .Const }},
+			Build: func({{ if .WithContainer }}ctn{{ else }}_{{ end }} *di.Container) (any, error) {
+				return {{ .Constructor }}({{ if .WithContainer }}ctn{{ end }}){{ if not .WithError }}, nil{{ end }}
+			},
+{{- if .Close }}
+			Close: func(obj any) error {
+				return obj.({{ .Type }}).Close()
+			},
+{{- end }}
+{{- if .Requires }}
+			Requires: []string{ {{- join .Requires ", " -}} },
+{{- end }}
+{{- if .Lazy }}
+			Lazy: true,
+{{- end }}
+		},
+{{- end }}
+	)
+}
+`
+
+type tplData struct {
+	Name        string
+	Register    string
+	StdImports  []string
+	Imports     []string
+	Definitions []tplDefinition
+}
+
+type tplDefinition struct {
+	Definition
+
+	Const  string
+	Getter string
+	Quoted string
+}
+
+// Render returns the generated code for the package
+func Render(pkg *Package, register string) ([]byte, error) {
+	tpl, err := template.New("digen").Funcs(template.FuncMap{"join": strings.Join}).Parse(tplSource)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+
+	data := tplData{
+		Name:        pkg.Name,
+		Register:    register,
+		Definitions: renderDefinitions(pkg),
+	}
+
+	data.StdImports, data.Imports = renderImports(pkg)
+
+	buf := &bytes.Buffer{}
+
+	if err := tpl.Execute(buf, data); err != nil {
+		return nil, fmt.Errorf("execute template: %w", err)
+	}
+
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+
+	return code, nil
+}
+
+// renderImports returns the standard library and the other import specs of the generated file, sorted
+func renderImports(pkg *Package) (std []string, imports []string) {
+	imports = []string{strconv.Quote(diPath)}
+
+	for importPath, name := range pkg.Imports {
+		if importPath == diPath {
+			continue
+		}
+
+		spec := strconv.Quote(importPath)
+		if importName(importPath) != name {
+			spec = name + " " + spec
+		}
+
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			imports = append(imports, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+
+	sort.Strings(std)
+	sort.Strings(imports)
+
+	return std, imports
+}
+
+// renderDefinitions returns the template data of the definitions,
+// the requirements registered in the package are referenced by their constants
+func renderDefinitions(pkg *Package) []tplDefinition {
+	consts := make(map[string]string, len(pkg.Definitions))
+	defs := make([]tplDefinition, 0, len(pkg.Definitions))
+
+	for _, def := range pkg.Definitions {
+		consts[def.Name] = "Def" + identifier(def.Name)
+	}
+
+	for _, def := range pkg.Definitions {
+		requires := make([]string, 0, len(def.Requires))
+
+		for _, name := range def.Requires {
+			if c, ok := consts[name]; ok {
+				requires = append(requires, c)
+			} else {
+				requires = append(requires, strconv.Quote(name))
+			}
+		}
+
+		def.Requires = requires
+
+		defs = append(defs, tplDefinition{
+			Definition: def,
+			Const:      consts[def.Name],
+			Getter:     "Get" + identifier(def.Name),
+			Quoted:     strconv.Quote(def.Name),
+		})
+	}
+
+	return defs
+}
+
+// identifier converts the dependency name to the exported CamelCase identifier
+func identifier(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	b := strings.Builder{}
+
+	for _, part := range parts {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+
+		b.WriteString(string(runes))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/kukymbr/core2go/di"
+)
+
+type UserRepo struct {
+	Builder *strings.Builder
+}
+
+func (r *UserRepo) Close() error {
+	return nil
+}
+
+type UserService struct {
+	Repo *UserRepo
+}
+
+// NewBuilder creates a strings builder.
+//
+//di:def strings_builder
+func NewBuilder() *strings.Builder {
+	return &strings.Builder{}
+}
+
+//di:def user_repo close requires=strings_builder
+func NewUserRepo(ctn *di.Container) (*UserRepo, error) {
+	return &UserRepo{Builder: GetStringsBuilder(ctn)}, nil
+}
+
+//di:def user_service lazy requires=user_repo
+func NewUserService(ctn *di.Container) (*UserService, error) {
+	if !ctn.Has(DefUserRepo) {
+		return nil, errors.New("no repo")
+	}
+
+	return &UserService{Repo: GetUserRepo(ctn)}, nil
+}
@@ -0,0 +1,61 @@
+// Code generated by core2go-di-gen. DO NOT EDIT.
+
+package app
+
+import (
+	"strings"
+
+	"github.com/kukymbr/core2go/di"
+)
+
+// Dependencies names.
+const (
+	DefStringsBuilder = "strings_builder"
+	DefUserRepo       = "user_repo"
+	DefUserService    = "user_service"
+)
+
+// GetStringsBuilder returns the "strings_builder" dependency. Panics on error.
+func GetStringsBuilder(ctn *di.Container) *strings.Builder {
+	return ctn.Get(DefStringsBuilder).(*strings.Builder)
+}
+
+// GetUserRepo returns the "user_repo" dependency. Panics on error.
+func GetUserRepo(ctn *di.Container) *UserRepo {
+	return ctn.Get(DefUserRepo).(*UserRepo)
+}
+
+// GetUserService returns the "user_service" dependency. Panics on error.
+func GetUserService(ctn *di.Container) *UserService {
+	return ctn.Get(DefUserService).(*UserService)
+}
+
+// RegisterDefinitions adds the generated definitions to the di.Builder.
+func RegisterDefinitions(builder *di.Builder) error {
+	return builder.Add(
+		di.Def{
+			Name: DefStringsBuilder,
+			Build: func(_ *di.Container) (any, error) {
+				return NewBuilder(), nil
+			},
+		},
+		di.Def{
+			Name: DefUserRepo,
+			Build: func(ctn *di.Container) (any, error) {
+				return NewUserRepo(ctn)
+			},
+			Close: func(obj any) error {
+				return obj.(*UserRepo).Close()
+			},
+			Requires: []string{DefStringsBuilder},
+		},
+		di.Def{
+			Name: DefUserService,
+			Build: func(ctn *di.Container) (any, error) {
+				return NewUserService(ctn)
+			},
+			Requires: []string{DefUserRepo},
+			Lazy:     true,
+		},
+	)
+}
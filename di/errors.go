@@ -4,7 +4,16 @@ import "errors"
 
 // Builder errors
 var (
-	ErrDefinitionExists     = errors.New("definition already registered")
-	ErrBuildFunctionMissing = errors.New("definition build function is missing")
-	ErrDefinitionNotFound   = errors.New("definition not found")
+	ErrDefinitionExists       = errors.New("definition already registered")
+	ErrBuildFunctionMissing   = errors.New("definition build function is missing")
+	ErrDefinitionNotFound     = errors.New("definition not found")
+	ErrDependencyMissing      = errors.New("required dependency is not registered")
+	ErrCircularDependency     = errors.New("circular dependency detected")
+	ErrBuildFunctionAmbiguous = errors.New("definition has both build and factory functions")
+	ErrNotFactory             = errors.New("definition is not a factory")
+	ErrFactoryRebuild         = errors.New("factory definition cannot be rebuilt")
+	ErrFactoryWithoutArgs     = errors.New("factory definition must be requested with GetWith")
+	ErrArgsNotComparable      = errors.New("factory arguments are not comparable")
+	ErrTypeMismatch           = errors.New("dependency type mismatch")
+	ErrBuilderBuilt           = errors.New("container is already built, definitions cannot be added")
 )
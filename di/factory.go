@@ -0,0 +1,202 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+//nolint:gochecknoglobals
+var anyType = reflect.TypeOf((*any)(nil)).Elem()
+
+// instances is a cache of the factory built objects
+type instances struct {
+	mu   sync.Mutex
+	objs map[any]any
+	ord  []any
+}
+
+// newInstances creates new instances cache
+func newInstances() *instances {
+	return &instances{
+		objs: make(map[any]any),
+	}
+}
+
+// get returns cached object
+func (i *instances) get(key any) (obj any, ok bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	obj, ok = i.objs[key]
+
+	return obj, ok
+}
+
+// store saves object to the cache if key is not stored yet,
+// returns the stored object and the flag if given one was stored
+func (i *instances) store(key any, obj any) (stored any, ok bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if existing, exists := i.objs[key]; exists {
+		return existing, false
+	}
+
+	i.objs[key] = obj
+	i.ord = append(i.ord, key)
+
+	return obj, true
+}
+
+// len returns count of the cached objects
+func (i *instances) len() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return len(i.objs)
+}
+
+// close closes all cached objects and clears the cache
+func (i *instances) close(name string, fn CloseFn) (err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if fn != nil {
+		for _, key := range i.ord {
+			if objErr := fn(i.objs[key]); objErr != nil {
+				err = errors.Join(err, fmt.Errorf("%s %v: %w", name, key, objErr))
+			}
+		}
+	}
+
+	i.objs = make(map[any]any)
+	i.ord = nil
+
+	return err
+}
+
+// GetWith returns dependency built by the factory with the given arguments. Panics on error.
+func (c *Container) GetWith(name string, args ...any) (obj any) {
+	obj, err := c.SafeGetWith(name, args...)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return obj
+}
+
+// SafeGetWith returns dependency built by the factory with the given arguments.
+// If Def.Cache is enabled, objects are cached by the arguments compared with ==,
+// so pointers are compared by the address, not by the pointed value.
+// Returns ErrArgsNotComparable if the Def.Cache is enabled and any argument is not comparable.
+func (c *Container) SafeGetWith(name string, args ...any) (obj any, err error) {
+	base := c.root()
+
+	base.mu.RLock()
+	def, ok := base.defs[name]
+	base.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, ErrDefinitionNotFound)
+	}
+
+	if def.Factory == nil {
+		return nil, fmt.Errorf("%s: %w", name, ErrNotFactory)
+	}
+
+	base.getObservers().notify(Event{Type: EventResolve, Name: name})
+
+	if !def.Cache {
+		return def.buildWith(c, args)
+	}
+
+	return def.getCached(c, args)
+}
+
+// getCached returns the factory object cached by the arguments, builds and caches it if missing
+func (d *Def) getCached(ctn *Container, args []any) (obj any, err error) {
+	key, err := argsKey(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", d.Name, err)
+	}
+
+	if obj, ok := d.instances.get(key); ok {
+		return obj, nil
+	}
+
+	obj, err = d.buildWith(ctn, args)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, ok := d.instances.store(key, obj)
+	if !ok && d.Close != nil {
+		// Concurrent call has already built the object with the same arguments
+		if err := d.Close(obj); err != nil {
+			return nil, fmt.Errorf("%s: close duplicate: %w", d.Name, err)
+		}
+	}
+
+	return stored, nil
+}
+
+// isFactory checks if dependency is registered with the Factory function
+func (c *Container) isFactory(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	def, ok := c.defs[name]
+
+	return ok && def.Factory != nil
+}
+
+// buildWith builds new factory object
+func (d *Def) buildWith(ctn *Container, args []any) (obj any, err error) {
+	start := time.Now()
+
+	obs := ctn.getObservers()
+
+	obs.notify(Event{Type: EventBuildStart, Name: d.Name})
+
+	defer func() {
+		obs.notify(Event{Type: EventBuildFinish, Name: d.Name, Duration: time.Since(start), Err: err})
+	}()
+
+	err = recoverBuild(func() error {
+		obj, err = d.Factory(ctn, args...)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", d.Name, err)
+	}
+
+	return obj, nil
+}
+
+// argsKey returns cache key for the factory arguments: the array of the arguments.
+// Returns ErrArgsNotComparable if any of the arguments can't be compared with ==.
+func argsKey(args []any) (key any, err error) {
+	arr := reflect.New(reflect.ArrayOf(len(args), anyType)).Elem()
+
+	for i := range args {
+		arr.Index(i).Set(reflect.ValueOf(&args[i]).Elem())
+	}
+
+	key = arr.Interface()
+
+	// Hashing the key panics if any of the arguments is not comparable,
+	// including the comparable types holding not comparable values in the interface fields.
+	defer func() {
+		if recover() != nil {
+			key, err = nil, ErrArgsNotComparable
+		}
+	}()
+
+	_ = map[any]struct{}{key: {}}
+
+	return key, nil
+}
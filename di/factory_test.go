@@ -0,0 +1,139 @@
+package di_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tenantClient struct {
+	tenant string
+	closed bool
+}
+
+func getFactoryContainer(t *testing.T, cache bool) *di.Container {
+	builder := &di.Builder{}
+
+	err := builder.Add(di.Def{
+		Name: "tenant_client",
+		Factory: func(ctn *di.Container, args ...any) (obj any, err error) {
+			if len(args) != 1 {
+				return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+			}
+
+			return &tenantClient{tenant: args[0].(string)}, nil
+		},
+		Close: func(obj any) (err error) {
+			obj.(*tenantClient).closed = true
+
+			return nil
+		},
+		Cache: cache,
+	})
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	return ctn
+}
+
+func TestContainer_GetWith_WhenCached_ExpectSameInstancesClosed(t *testing.T) {
+	ctn := getFactoryContainer(t, true)
+
+	client1 := ctn.GetWith("tenant_client", "tenant1").(*tenantClient)
+	client2 := ctn.GetWith("tenant_client", "tenant2").(*tenantClient)
+
+	assert.Equal(t, "tenant1", client1.tenant)
+	assert.Equal(t, "tenant2", client2.tenant)
+	assert.Same(t, client1, ctn.GetWith("tenant_client", "tenant1"))
+
+	err := ctn.Close()
+	require.NoError(t, err)
+
+	assert.True(t, client1.closed)
+	assert.True(t, client2.closed)
+}
+
+func TestContainer_GetWith_WhenCachedByValue_ExpectNoCollisions(t *testing.T) {
+	builder := &di.Builder{}
+
+	err := builder.Add(di.Def{
+		Name: "client",
+		Factory: func(ctn *di.Container, args ...any) (obj any, err error) {
+			return &tenantClient{tenant: fmt.Sprint(args...)}, nil
+		},
+		Cache: true,
+	})
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	type tenantKey struct {
+		Name string
+		ID   int
+	}
+
+	byStruct := ctn.GetWith("client", tenantKey{Name: "tenant", ID: 1})
+
+	assert.Same(t, byStruct, ctn.GetWith("client", tenantKey{Name: "tenant", ID: 1}))
+	assert.NotSame(t, byStruct, ctn.GetWith("client", tenantKey{Name: "tenant", ID: 2}))
+	assert.NotSame(t, ctn.GetWith("client", 1), ctn.GetWith("client", "1"))
+	assert.NotSame(t, ctn.GetWith("client", "a", "b"), ctn.GetWith("client", "a"))
+	assert.Same(t, ctn.GetWith("client"), ctn.GetWith("client"))
+}
+
+func TestContainer_GetWith_WhenNotCached_ExpectNewInstances(t *testing.T) {
+	ctn := getFactoryContainer(t, false)
+
+	client1 := ctn.GetWith("tenant_client", "tenant1").(*tenantClient)
+	client2 := ctn.GetWith("tenant_client", "tenant1").(*tenantClient)
+
+	assert.NotSame(t, client1, client2)
+
+	err := ctn.Close()
+	require.NoError(t, err)
+
+	assert.False(t, client1.closed)
+}
+
+func TestContainer_GetWith_WhenError_ExpectError(t *testing.T) {
+	ctn := getFactoryContainer(t, true)
+
+	_, err := ctn.SafeGet("tenant_client")
+	assert.ErrorIs(t, err, di.ErrFactoryWithoutArgs)
+
+	_, err = ctn.SafeGetWith("tenant_client", []string{"tenant1"})
+	assert.ErrorIs(t, err, di.ErrArgsNotComparable)
+
+	_, err = ctn.SafeGetWith("tenant_client", struct{ Value any }{Value: map[string]int{}})
+	assert.ErrorIs(t, err, di.ErrArgsNotComparable)
+
+	_, err = ctn.SafeGetWith("unknown", 1)
+	assert.ErrorIs(t, err, di.ErrDefinitionNotFound)
+
+	err = ctn.Rebuild("tenant_client")
+	assert.ErrorIs(t, err, di.ErrFactoryRebuild)
+}
+
+func TestContainer_GetWith_WhenNotFactory_ExpectError(t *testing.T) {
+	builder := &di.Builder{}
+
+	err := builder.Add(di.Def{
+		Name: "testname1",
+		Build: func(ctn *di.Container) (obj any, err error) {
+			return "testval1", nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	_, err = ctn.SafeGetWith("testname1", 1)
+	assert.ErrorIs(t, err, di.ErrNotFactory)
+}
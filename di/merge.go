@@ -0,0 +1,76 @@
+package di
+
+import (
+	"fmt"
+)
+
+// ConflictPolicy defines how to resolve definitions names conflict on Builder.Merge
+type ConflictPolicy int
+
+// Definitions names conflict policies
+const (
+	// ConflictError fails the merge if the definition is already registered
+	ConflictError ConflictPolicy = iota
+
+	// ConflictKeep keeps the already registered definition
+	ConflictKeep
+
+	// ConflictOverride replaces the already registered definition with the merged one
+	ConflictOverride
+)
+
+// Clone returns new unbuilt Builder with the same definitions and observers.
+// Each Clone call allows to build an independent Container from the same definitions.
+func (b *Builder) Clone() *Builder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.initContainer()
+
+	b.ctn.mu.RLock()
+	defer b.ctn.mu.RUnlock()
+
+	clone := &Builder{}
+	clone.initContainer()
+
+	clone.ctn.observers = append(clone.ctn.observers, b.ctn.observers...)
+	clone.ctn.ord = append(clone.ctn.ord, b.ctn.ord...)
+
+	for name, def := range b.ctn.defs {
+		clone.ctn.defs[name] = def.reset()
+	}
+
+	return clone
+}
+
+// Merge adds definitions from the other Builder, resolving names conflicts with the given policy.
+// With ConflictError policy, nothing is merged if any conflict is found.
+// Returns ErrBuilderBuilt if the Container is already built.
+func (b *Builder) Merge(other *Builder, policy ConflictPolicy) error {
+	src := other.Clone().ctn
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.initContainer()
+
+	if b.built {
+		return ErrBuilderBuilt
+	}
+
+	if policy == ConflictError {
+		for _, name := range src.ord {
+			if _, exists := b.ctn.defs[name]; exists {
+				return fmt.Errorf("%s: %w", name, ErrDefinitionExists)
+			}
+		}
+	}
+
+	for _, name := range src.ord {
+		if err := b.add(src.defs[name], policy); err != nil {
+			return fmt.Errorf("merge: %w", err)
+		}
+	}
+
+	return nil
+}
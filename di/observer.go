@@ -0,0 +1,88 @@
+package di
+
+import (
+	"time"
+)
+
+// EventType is a type of the Container event
+type EventType int
+
+// Container event types
+const (
+	// EventDefinitionAdded is emitted when the Def is added to the Builder
+	EventDefinitionAdded EventType = iota + 1
+
+	// EventBuildStart is emitted before the Def's build function call
+	EventBuildStart
+
+	// EventBuildFinish is emitted after the Def's build function call
+	EventBuildFinish
+
+	// EventResolve is emitted on each dependency request from the Container
+	EventResolve
+
+	// EventCloseStart is emitted before the Container close
+	EventCloseStart
+
+	// EventCloseFinish is emitted after the Container close
+	EventCloseFinish
+)
+
+// String returns event type name
+func (t EventType) String() string {
+	switch t {
+	case EventDefinitionAdded:
+		return "definition_added"
+	case EventBuildStart:
+		return "build_start"
+	case EventBuildFinish:
+		return "build_finish"
+	case EventResolve:
+		return "resolve"
+	case EventCloseStart:
+		return "close_start"
+	case EventCloseFinish:
+		return "close_finish"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a Container event
+type Event struct {
+	// Type is an event type
+	Type EventType
+
+	// Name is a dependency name, empty for the container-wide events
+	Name string
+
+	// Duration is an operation duration, filled for the finish events
+	Duration time.Duration
+
+	// Err is an operation error, filled for the finish events
+	Err error
+}
+
+// Observer receives the Container events.
+// Observer is called synchronously and must not call the Container's methods.
+type Observer interface {
+	Observe(event Event)
+}
+
+// ObserverFunc is a function implementing the Observer interface
+type ObserverFunc func(event Event)
+
+// Observe calls the function
+func (fn ObserverFunc) Observe(event Event) {
+	fn(event)
+}
+
+// observers is a list of the Observer instances
+type observers []Observer
+
+// notify sends event to all observers
+func (o observers) notify(event Event) {
+	for _, obs := range o {
+		obs.Observe(event)
+	}
+}
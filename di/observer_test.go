@@ -0,0 +1,160 @@
+package di_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecorder_WhenContainerUsed_ExpectEventsRecorded(t *testing.T) {
+	recorder := &di.Recorder{}
+	builder := &di.Builder{}
+
+	builder.AddObserver(recorder)
+
+	err := builder.Add(
+		di.Def{
+			Name: "eager",
+			Build: func(ctn *di.Container) (obj any, err error) {
+				return "eager", nil
+			},
+		},
+		di.Def{
+			Name: "lazy",
+			Build: func(ctn *di.Container) (obj any, err error) {
+				return "lazy", errors.New("build error")
+			},
+			Lazy: true,
+		},
+	)
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	_ = ctn.Get("eager")
+	_ = ctn.Get("eager")
+
+	_, err = ctn.SafeGet("lazy")
+	assert.Error(t, err)
+
+	err = ctn.Close()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, recorder.Count(di.EventDefinitionAdded, ""))
+	assert.Equal(t, 1, recorder.Count(di.EventBuildStart, "eager"))
+	assert.Equal(t, 1, recorder.Count(di.EventBuildFinish, "lazy"))
+	assert.Equal(t, 2, recorder.Count(di.EventResolve, "eager"))
+	assert.Equal(t, 1, recorder.Count(di.EventCloseStart, ""))
+	assert.Equal(t, 1, recorder.Count(di.EventCloseFinish, ""))
+
+	for _, event := range recorder.Filter(di.EventBuildFinish) {
+		if event.Name == "lazy" {
+			assert.Error(t, event.Err)
+		} else {
+			assert.NoError(t, event.Err)
+		}
+	}
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Events())
+}
+
+func TestLogObserver_WhenEventObserved_ExpectLogged(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	obs := di.NewLogObserver(zap.New(core))
+
+	obs.Observe(di.Event{Type: di.EventBuildFinish, Name: "test"})
+	obs.Observe(di.Event{Type: di.EventBuildFinish, Name: "test", Err: errors.New("build error")})
+
+	require.Equal(t, 2, logs.Len())
+	assert.Equal(t, zap.DebugLevel, logs.All()[0].Level)
+	assert.Equal(t, zap.WarnLevel, logs.All()[1].Level)
+	assert.Equal(t, "build_finish", logs.All()[0].ContextMap()["event"])
+}
+
+func TestObserverFunc_WhenContainerClosed_ExpectCalled(t *testing.T) {
+	builder := &di.Builder{}
+	called := 0
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	ctn.AddObserver(di.ObserverFunc(func(event di.Event) {
+		called++
+	}))
+
+	err = ctn.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, called)
+}
+
+func TestContainer_Close_WhenObserverCallsContainer_ExpectNoDeadlock(t *testing.T) {
+	builder := &di.Builder{}
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	lens := make([]int, 0)
+
+	ctn.AddObserver(di.ObserverFunc(func(event di.Event) {
+		lens = append(lens, ctn.Len())
+	}))
+
+	done := make(chan error)
+
+	go func() {
+		done <- ctn.Close()
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 0}, lens)
+	case <-time.After(time.Second):
+		t.Fatal("container close is deadlocked")
+	}
+}
+
+func TestContainer_AddObserver_WhenResolvedConcurrently_ExpectNoRace(t *testing.T) {
+	builder := &di.Builder{}
+
+	err := builder.Add(di.Def{
+		Name: "dep",
+		Build: func(ctn *di.Container) (any, error) {
+			return "dep", nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			ctn.AddObserver(&di.Recorder{})
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			_ = ctn.Get("dep")
+		}
+	}()
+
+	wg.Wait()
+}
@@ -0,0 +1,43 @@
+package di
+
+import (
+	"go.uber.org/zap"
+)
+
+// NewLogObserver creates new Observer writing the Container events to the zap log.
+func NewLogObserver(log *zap.Logger) Observer {
+	if log == nil {
+		log = zap.NewNop()
+	}
+
+	return &logObserver{
+		log: log.With(zap.String("who", "core2go.di.Container")),
+	}
+}
+
+type logObserver struct {
+	log *zap.Logger
+}
+
+func (o *logObserver) Observe(event Event) {
+	fields := []zap.Field{
+		zap.String("event", event.Type.String()),
+	}
+
+	if event.Name != "" {
+		fields = append(fields, zap.String("name", event.Name))
+	}
+
+	if event.Duration > 0 {
+		fields = append(fields, zap.Duration("duration", event.Duration))
+	}
+
+	if event.Err != nil {
+		fields = append(fields, zap.Error(event.Err))
+		o.log.Warn("DI event", fields...)
+
+		return
+	}
+
+	o.log.Debug("DI event", fields...)
+}
@@ -0,0 +1,71 @@
+package di
+
+import (
+	"sync"
+)
+
+// Recorder is an Observer storing the Container events in memory.
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// Observe records the event
+func (r *Recorder) Observe(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+// Events returns a copy of the recorded events
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]Event, len(r.events))
+	copy(events, r.events)
+
+	return events
+}
+
+// Filter returns recorded events of the given type
+func (r *Recorder) Filter(eventType EventType) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]Event, 0)
+
+	for _, event := range r.events {
+		if event.Type == eventType {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// Count returns count of the recorded events of the given type for the given dependency name.
+// If name is empty, events for all names are counted.
+func (r *Recorder) Count(eventType EventType, name string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+
+	for _, event := range r.events {
+		if event.Type == eventType && (name == "" || event.Name == name) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Reset removes all recorded events
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = nil
+}
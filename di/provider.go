@@ -0,0 +1,67 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NewProvider creates new Provider of the Container's dependency
+func NewProvider[T any](ctn *Container, name string) *Provider[T] {
+	return &Provider[T]{
+		ctn:  ctn,
+		name: name,
+	}
+}
+
+// ProviderDef returns definition of the Provider for the target dependency.
+// Allows to inject the Provider instead of the dependency itself,
+// for example, to break initialization cycles or to defer expensive builds.
+func ProviderDef[T any](name string, target string) Def {
+	return Def{
+		Name:     name,
+		Requires: []string{target},
+		Build: func(ctn *Container) (obj any, err error) {
+			return NewProvider[T](ctn, target), nil
+		},
+	}
+}
+
+// Provider is a typed handle of the Container's dependency.
+// The dependency is not resolved until the Get or SafeGet call,
+// lazy dependencies are built on the first call.
+type Provider[T any] struct {
+	ctn  *Container
+	name string
+}
+
+// Name returns the target dependency name
+func (p *Provider[T]) Name() string {
+	return p.name
+}
+
+// Get returns the target dependency. Panics on error.
+func (p *Provider[T]) Get() T {
+	obj, err := p.SafeGet()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return obj
+}
+
+// SafeGet returns the target dependency
+func (p *Provider[T]) SafeGet() (obj T, err error) {
+	raw, err := p.ctn.SafeGet(p.name)
+	if err != nil {
+		return obj, err
+	}
+
+	obj, ok := raw.(T)
+	if !ok {
+		expected := reflect.TypeOf((*T)(nil)).Elem()
+
+		return obj, fmt.Errorf("%s: %w: expected %s, got %T", p.name, ErrTypeMismatch, expected, raw)
+	}
+
+	return obj, nil
+}
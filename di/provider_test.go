@@ -0,0 +1,82 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type providerService struct {
+	repo *di.Provider[*providerRepo]
+}
+
+type providerRepo struct {
+	service *providerService
+}
+
+func TestProvider_WhenInjected_ExpectCycleResolved(t *testing.T) {
+	builder := &di.Builder{}
+	built := 0
+
+	err := builder.Add(
+		di.ProviderDef[*providerRepo]("repo_provider", "repo"),
+		di.Def{
+			Name:     "service",
+			Requires: []string{"repo_provider"},
+			Build: func(ctn *di.Container) (obj any, err error) {
+				return &providerService{
+					repo: ctn.Get("repo_provider").(*di.Provider[*providerRepo]),
+				}, nil
+			},
+		},
+		di.Def{
+			Name:     "repo",
+			Requires: []string{"service"},
+			Build: func(ctn *di.Container) (obj any, err error) {
+				built++
+
+				return &providerRepo{service: ctn.Get("service").(*providerService)}, nil
+			},
+			Lazy: true,
+		},
+	)
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	srv := ctn.Get("service").(*providerService)
+	assert.Equal(t, 0, built)
+
+	repo := srv.repo.Get()
+	require.NotNil(t, repo)
+	assert.Same(t, srv, repo.service)
+	assert.Same(t, repo, srv.repo.Get())
+	assert.Equal(t, 1, built)
+	assert.Equal(t, "repo", srv.repo.Name())
+}
+
+func TestProvider_WhenTypeMismatch_ExpectError(t *testing.T) {
+	builder := &di.Builder{}
+
+	err := builder.Add(di.Def{
+		Name: "testname1",
+		Build: func(ctn *di.Container) (obj any, err error) {
+			return "testval1", nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	_, err = di.NewProvider[int](ctn, "testname1").SafeGet()
+	assert.ErrorIs(t, err, di.ErrTypeMismatch)
+
+	_, err = di.NewProvider[string](ctn, "unknown").SafeGet()
+	assert.ErrorIs(t, err, di.ErrDefinitionNotFound)
+
+	assert.Equal(t, "testval1", di.NewProvider[string](ctn, "testname1").Get())
+}
@@ -0,0 +1,231 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RebuildOption is an option of the Container.Rebuild call
+type RebuildOption func(opt *rebuildOptions)
+
+// WithDrain sets a delay before closing the replaced instances.
+// Callers holding the old instances can use them until the delay is over.
+func WithDrain(delay time.Duration) RebuildOption {
+	return func(opt *rebuildOptions) {
+		opt.drain = delay
+	}
+}
+
+// WithCascade enables rebuild of the built dependents,
+// i.e. definitions having the rebuilt one in their Def.Requires list.
+func WithCascade() RebuildOption {
+	return func(opt *rebuildOptions) {
+		opt.cascade = true
+	}
+}
+
+type rebuildOptions struct {
+	drain   time.Duration
+	cascade bool
+}
+
+// Rebuild builds new instance of the dependency and replaces the old one with it.
+// The old instance is closed after the drain delay (see WithDrain).
+// Rebuild blocks the caller until the old instance is closed, call it in a goroutine to not wait for the drain;
+// other rebuilds are not blocked by the drain.
+// If the new instance build failed, the old one is kept.
+// With the cascade enabled, a failure rolls back all the already replaced instances
+// and closes their new ones, so the kept instances never refer to the closed dependencies.
+func (c *Container) Rebuild(name string, options ...RebuildOption) error {
+	if c.origin != nil {
+		return c.origin.Rebuild(name, options...)
+	}
+
+	opt := &rebuildOptions{}
+	for _, fn := range options {
+		fn(opt)
+	}
+
+	replaced, err := c.replace(name, opt.cascade)
+
+	return errors.Join(err, closeReplaced(replaced, opt.drain))
+}
+
+// replace rebuilds the definition (and its dependents with the cascade), returns the replaced definitions.
+// On failure, returns the error and the new definitions rolled back.
+func (c *Container) replace(name string, cascade bool) ([]Def, error) {
+	c.rmu.Lock()
+	defer c.rmu.Unlock()
+
+	names, err := c.rebuildList(name, cascade)
+	if err != nil {
+		return nil, err
+	}
+
+	replaced := make([]Def, 0, len(names))
+
+	for _, n := range names {
+		old, err := c.swap(n)
+		if err != nil {
+			return c.rollback(replaced), fmt.Errorf("%s dependency rebuild failed: %w", n, err)
+		}
+
+		replaced = append(replaced, old)
+	}
+
+	return replaced, nil
+}
+
+// rollback restores the replaced definitions, returns the new ones replaced back
+func (c *Container) rollback(replaced []Def) []Def {
+	rebuilt := make([]Def, 0, len(replaced))
+
+	for i := len(replaced) - 1; i >= 0; i-- {
+		old := replaced[i]
+		lock := c.buildLock(old.Name)
+
+		lock.Lock()
+		c.mu.Lock()
+
+		rebuilt = append(rebuilt, c.defs[old.Name])
+		c.defs[old.Name] = old
+
+		c.mu.Unlock()
+		lock.Unlock()
+	}
+
+	return rebuilt
+}
+
+// rebuildList returns names of the definitions to rebuild, dependencies first
+func (c *Container) rebuildList(name string, cascade bool) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	def, ok := c.defs[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, ErrDefinitionNotFound)
+	}
+
+	if def.Factory != nil {
+		return nil, fmt.Errorf("%s: %w", name, ErrFactoryRebuild)
+	}
+
+	if !cascade {
+		return []string{name}, nil
+	}
+
+	affected := c.dependents(name)
+	names := make([]string, 0, len(affected))
+	added := make(map[string]bool, len(affected))
+
+	for len(names) < len(affected) {
+		next := ""
+
+		for _, n := range c.ord {
+			if affected[n] && !added[n] && c.requiresAdded(n, affected, added) {
+				next = n
+
+				break
+			}
+		}
+
+		if next == "" {
+			return nil, fmt.Errorf("%s: %w", name, ErrCircularDependency)
+		}
+
+		names = append(names, next)
+		added[next] = true
+	}
+
+	return names, nil
+}
+
+// dependents returns set of the built definitions requiring the given one (directly or not),
+// including the given one itself
+func (c *Container) dependents(name string) map[string]bool {
+	affected := map[string]bool{name: true}
+
+	for found := true; found; {
+		found = false
+
+		for _, n := range c.ord {
+			def := c.defs[n]
+			if affected[n] || !def.built {
+				continue
+			}
+
+			for _, dep := range def.Requires {
+				if affected[dep] {
+					affected[n] = true
+					found = true
+
+					break
+				}
+			}
+		}
+	}
+
+	return affected
+}
+
+// requiresAdded checks if all affected requirements of the definition are already in the list
+func (c *Container) requiresAdded(name string, affected map[string]bool, added map[string]bool) bool {
+	for _, dep := range c.defs[name].Requires {
+		if affected[dep] && !added[dep] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// swap builds new instance of the definition and replaces the old one with it.
+// Holds the build lock of the definition, so the concurrent lazy build doesn't overwrite the new instance.
+func (c *Container) swap(name string) (old Def, err error) {
+	lock := c.buildLock(name)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	c.mu.RLock()
+	def := c.defs[name]
+	c.mu.RUnlock()
+
+	def = def.reset()
+
+	if err := def.build(c.scope(name)); err != nil {
+		return Def{}, err
+	}
+
+	c.mu.Lock()
+	old = c.defs[name]
+	c.defs[name] = def
+	c.mu.Unlock()
+
+	return old, nil
+}
+
+// closeReplaced waits for the drain delay and closes the replaced instances
+func closeReplaced(defs []Def, drain time.Duration) (err error) {
+	if len(defs) == 0 {
+		return nil
+	}
+
+	if drain > 0 {
+		time.Sleep(drain)
+	}
+
+	for _, def := range defs {
+		if !def.built || def.Close == nil {
+			continue
+		}
+
+		if defErr := def.Close(def.obj); defErr != nil {
+			err = errors.Join(err, fmt.Errorf("%s: %w", def.Name, defErr))
+		}
+	}
+
+	return err
+}
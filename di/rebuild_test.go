@@ -0,0 +1,250 @@
+package di_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rebuildItem struct {
+	version int64
+	dep     *rebuildItem
+	closed  atomic.Bool
+}
+
+func getRebuildContainer(t *testing.T, failVersion int64) *di.Container {
+	builder := &di.Builder{}
+	version := atomic.Int64{}
+
+	err := builder.Add(
+		di.Def{
+			Name: "pool",
+			Build: func(ctn *di.Container) (obj any, err error) {
+				v := version.Add(1)
+				if v == failVersion {
+					return nil, errors.New("build error")
+				}
+
+				return &rebuildItem{version: v}, nil
+			},
+			Close: func(obj any) (err error) {
+				obj.(*rebuildItem).closed.Store(true)
+
+				return nil
+			},
+		},
+		di.Def{
+			Name:     "repo",
+			Requires: []string{"pool"},
+			Build: func(ctn *di.Container) (obj any, err error) {
+				return &rebuildItem{dep: ctn.Get("pool").(*rebuildItem)}, nil
+			},
+			Close: func(obj any) (err error) {
+				obj.(*rebuildItem).closed.Store(true)
+
+				return nil
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	return ctn
+}
+
+func TestContainer_Rebuild_WhenCascade_ExpectDependentsRebuilt(t *testing.T) {
+	ctn := getRebuildContainer(t, 0)
+
+	oldPool := ctn.Get("pool").(*rebuildItem)
+	oldRepo := ctn.Get("repo").(*rebuildItem)
+
+	err := ctn.Rebuild("pool", di.WithCascade())
+	require.NoError(t, err)
+
+	newPool := ctn.Get("pool").(*rebuildItem)
+	newRepo := ctn.Get("repo").(*rebuildItem)
+
+	assert.Equal(t, int64(2), newPool.version)
+	assert.Same(t, newPool, newRepo.dep)
+	assert.True(t, oldPool.closed.Load())
+	assert.True(t, oldRepo.closed.Load())
+	assert.False(t, newPool.closed.Load())
+	assert.False(t, newRepo.closed.Load())
+}
+
+func TestContainer_Rebuild_WhenDrain_ExpectOldWorkingUntilDrained(t *testing.T) {
+	ctn := getRebuildContainer(t, 0)
+
+	oldPool := ctn.Get("pool").(*rebuildItem)
+	done := make(chan error)
+
+	go func() {
+		done <- ctn.Rebuild("pool", di.WithDrain(100*time.Millisecond))
+	}()
+
+	assert.Eventually(t, func() bool {
+		return ctn.Get("pool").(*rebuildItem) != oldPool
+	}, time.Second, 5*time.Millisecond)
+
+	assert.False(t, oldPool.closed.Load())
+	assert.NoError(t, <-done)
+	assert.True(t, oldPool.closed.Load())
+
+	repo := ctn.Get("repo").(*rebuildItem)
+	assert.Same(t, oldPool, repo.dep)
+}
+
+func TestContainer_Rebuild_WhenBuildFailed_ExpectOldKept(t *testing.T) {
+	ctn := getRebuildContainer(t, 2)
+
+	oldPool := ctn.Get("pool").(*rebuildItem)
+
+	err := ctn.Rebuild("pool", di.WithCascade())
+	assert.Error(t, err)
+
+	assert.Same(t, oldPool, ctn.Get("pool"))
+	assert.False(t, oldPool.closed.Load())
+}
+
+func TestContainer_Rebuild_WhenCascadeStepFailed_ExpectRolledBack(t *testing.T) {
+	builder := &di.Builder{}
+	repoBuilds := atomic.Int32{}
+	pools := make([]*rebuildItem, 0)
+
+	err := builder.Add(
+		di.Def{
+			Name: "pool",
+			Build: func(ctn *di.Container) (obj any, err error) {
+				pool := &rebuildItem{version: int64(len(pools) + 1)}
+				pools = append(pools, pool)
+
+				return pool, nil
+			},
+			Close: func(obj any) (err error) {
+				obj.(*rebuildItem).closed.Store(true)
+
+				return nil
+			},
+		},
+		di.Def{
+			Name:     "repo",
+			Requires: []string{"pool"},
+			Build: func(ctn *di.Container) (obj any, err error) {
+				if repoBuilds.Add(1) == 2 {
+					return nil, errors.New("build error")
+				}
+
+				return &rebuildItem{dep: ctn.Get("pool").(*rebuildItem)}, nil
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	oldPool := ctn.Get("pool").(*rebuildItem)
+	oldRepo := ctn.Get("repo").(*rebuildItem)
+
+	err = ctn.Rebuild("pool", di.WithCascade())
+	assert.ErrorContains(t, err, "repo dependency rebuild failed")
+
+	require.Len(t, pools, 2)
+	assert.Same(t, oldPool, ctn.Get("pool"))
+	assert.Same(t, oldRepo, ctn.Get("repo"))
+	assert.Same(t, oldPool, oldRepo.dep)
+	assert.False(t, oldPool.closed.Load())
+	assert.True(t, pools[1].closed.Load())
+}
+
+func TestContainer_Rebuild_WhenUnknown_ExpectError(t *testing.T) {
+	ctn := getRebuildContainer(t, 0)
+
+	err := ctn.Rebuild("unknown")
+
+	assert.ErrorIs(t, err, di.ErrDefinitionNotFound)
+}
+
+func TestContainer_Rebuild_WhenLazyBuiltConcurrently_ExpectReplacedClosed(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		builder := &di.Builder{}
+		mu := sync.Mutex{}
+		items := make([]*rebuildItem, 0)
+
+		err := builder.Add(di.Def{
+			Name: "conn",
+			Lazy: true,
+			Build: func(ctn *di.Container) (obj any, err error) {
+				item := &rebuildItem{}
+
+				// Widen the window for the concurrent build
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				items = append(items, item)
+				mu.Unlock()
+
+				return item, nil
+			},
+			Close: func(obj any) (err error) {
+				obj.(*rebuildItem).closed.Store(true)
+
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		ctn, err := builder.Build()
+		require.NoError(t, err)
+
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			_ = ctn.Get("conn")
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, ctn.Rebuild("conn"))
+		}()
+
+		wg.Wait()
+
+		current := ctn.Get("conn").(*rebuildItem)
+
+		for _, item := range items {
+			assert.Equal(t, item != current, item.closed.Load())
+		}
+	}
+}
+
+func TestContainer_Rebuild_WhenDraining_ExpectOtherRebuildsNotBlocked(t *testing.T) {
+	ctn := getRebuildContainer(t, 0)
+	done := make(chan error)
+
+	go func() {
+		done <- ctn.Rebuild("pool", di.WithDrain(time.Second))
+	}()
+
+	assert.Eventually(t, func() bool {
+		return ctn.Get("pool").(*rebuildItem).version == 2
+	}, time.Second, time.Millisecond)
+
+	start := time.Now()
+
+	assert.NoError(t, ctn.Rebuild("repo"))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.NoError(t, <-done)
+}
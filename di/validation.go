@@ -0,0 +1,66 @@
+package di
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError is an aggregated error of the definitions validation on Build
+type ValidationError struct {
+	Errors []error
+}
+
+// Error returns all validation errors as a string
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("definitions validation failed: %s", strings.Join(messages, "; "))
+}
+
+// Unwrap returns the validation errors
+func (e *ValidationError) Unwrap() []error {
+	return e.Errors
+}
+
+// validate checks all registered definitions, returns *ValidationError if any check failed
+func (b *Builder) validate() error {
+	var errs []error
+
+	for _, name := range b.ctn.ord {
+		errs = append(errs, b.validateDef(b.ctn.defs[name])...)
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+
+	return nil
+}
+
+// validateDef checks the definition, returns the failed checks errors
+func (b *Builder) validateDef(def Def) (errs []error) {
+	switch {
+	case def.Build == nil && def.Factory == nil:
+		errs = append(errs, fmt.Errorf("%s: %w", def.Name, ErrBuildFunctionMissing))
+	case def.Build != nil && def.Factory != nil:
+		errs = append(errs, fmt.Errorf("%s: %w", def.Name, ErrBuildFunctionAmbiguous))
+	}
+
+	for _, dep := range def.Requires {
+		if _, ok := b.ctn.defs[dep]; !ok {
+			errs = append(errs, fmt.Errorf("%s: requires %s: %w", def.Name, dep, ErrDependencyMissing))
+		}
+	}
+
+	if def.Verify != nil {
+		if err := def.Verify(b.ctn); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", def.Name, err))
+		}
+	}
+
+	return errs
+}
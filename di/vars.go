@@ -0,0 +1,58 @@
+package di
+
+// DefVars is a dependency state exposed by the Container.Vars
+type DefVars struct {
+	Built     bool   `json:"built"`
+	Lazy      bool   `json:"lazy"`
+	Factory   bool   `json:"factory"`
+	Instances int    `json:"instances,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+}
+
+// ContainerVars is a Container state exposed by the Container.Vars
+type ContainerVars struct {
+	Built       int                `json:"built"`
+	Pending     int                `json:"pending"`
+	Definitions map[string]DefVars `json:"definitions"`
+}
+
+// Vars returns the Container state
+func (c *Container) Vars() ContainerVars {
+	if c.origin != nil {
+		return c.origin.Vars()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	vars := ContainerVars{
+		Definitions: make(map[string]DefVars, len(c.defs)),
+	}
+
+	for name, def := range c.defs {
+		dv := DefVars{
+			Built:   def.built,
+			Lazy:    def.Lazy,
+			Factory: def.Factory != nil,
+		}
+
+		if def.built {
+			dv.BuildTime = def.duration.String()
+		}
+
+		if def.instances != nil {
+			dv.Instances = def.instances.len()
+		}
+
+		switch {
+		case def.built:
+			vars.Built++
+		case def.Factory == nil:
+			vars.Pending++
+		}
+
+		vars.Definitions[name] = dv
+	}
+
+	return vars
+}
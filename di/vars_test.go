@@ -0,0 +1,57 @@
+package di_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainer_Vars(t *testing.T) {
+	builder := &di.Builder{}
+
+	err := builder.Add(
+		di.Def{
+			Name: "eager",
+			Build: func(ctn *di.Container) (obj any, err error) {
+				return "eager", nil
+			},
+		},
+		di.Def{
+			Name: "lazy",
+			Build: func(ctn *di.Container) (obj any, err error) {
+				return "lazy", nil
+			},
+			Lazy: true,
+		},
+	)
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	vars := ctn.Vars()
+
+	assert.Equal(t, 1, vars.Built)
+	assert.Equal(t, 1, vars.Pending)
+	assert.True(t, vars.Definitions["eager"].Built)
+	assert.NotEmpty(t, vars.Definitions["eager"].BuildTime)
+	assert.False(t, vars.Definitions["lazy"].Built)
+	assert.True(t, vars.Definitions["lazy"].Lazy)
+
+	_ = ctn.Get("lazy")
+
+	assert.Equal(t, 2, ctn.Vars().Built)
+	assert.Equal(t, 0, ctn.Vars().Pending)
+}
+
+func TestContainer_Vars_ExpectDebugVarsNotRegistered(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+
+	_, pattern := http.DefaultServeMux.Handler(req)
+
+	assert.Empty(t, pattern)
+}
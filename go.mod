@@ -5,7 +5,6 @@ go 1.20
 require (
 	github.com/stretchr/testify v1.8.4
 	go.uber.org/zap v1.24.0
-	golang.org/x/sync v0.4.0
 )
 
 require (
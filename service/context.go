@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ctxKey int
+
+const (
+	loggerCtxKey ctxKey = iota
+	readyCtxKey
+)
+
+// LoggerFromContext returns the logger passed by the Service to the Runner.
+// If no logger in the context, the no-op logger is returned.
+func LoggerFromContext(ctx context.Context) *zap.Logger {
+	if log, ok := ctx.Value(loggerCtxKey).(*zap.Logger); ok && log != nil {
+		return log
+	}
+
+	return zap.NewNop()
+}
+
+// contextWithLogger returns the context with the logger.
+func contextWithLogger(ctx context.Context, log *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey, log)
+}
+
+// Ready reports the Runner executed with the context is ready,
+// so the runners of the next stage can be started, see InStage.
+// The Runner is considered ready when it returned as well.
+// Calls without the Service's runner context are ignored.
+func Ready(ctx context.Context) {
+	if ready, ok := ctx.Value(readyCtxKey).(func()); ok && ready != nil {
+		ready()
+	}
+}
+
+// contextWithReady returns the context with the readiness report function.
+func contextWithReady(ctx context.Context, ready func()) context.Context {
+	return context.WithValue(ctx, readyCtxKey, ready)
+}
+
+// detachedContext is a context with the parent's values, which is not canceled with the parent.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (c detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (c detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key any) any {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCronSpec is returned when the cron spec can't be parsed.
+var ErrInvalidCronSpec = errors.New("invalid cron spec")
+
+// Schedule is a jobs schedule.
+type Schedule interface {
+	// Next returns the next activation time after the given one, zero time if none.
+	Next(t time.Time) time.Time
+}
+
+// ParseCron parses the standard cron spec into the Schedule.
+//
+// The spec has 5 fields (minute, hour, day of month, month, day of week)
+// or 6 fields with the leading seconds field.
+// Fields support the wildcards (* or ?), values, ranges (1-5), lists (1,3,5), steps (*/15, 0-30/5)
+// and month and day of week names (JAN-DEC, SUN-SAT).
+// The descriptors @yearly (@annually), @monthly, @weekly, @daily (@midnight) and @hourly are supported as well.
+// If both day of month and day of week are restricted (don't start with * or ?), the day matching any of them is activated.
+// Specs never matching any day (e.g. "0 0 31 4 *") are rejected.
+//
+// The time zone can be set with the CRON_TZ= (or TZ=) prefix, e.g. "CRON_TZ=Europe/Berlin 0 9 * * MON-FRI",
+// otherwise the time zone of the time passed to the Next is used.
+func ParseCron(spec string) (Schedule, error) {
+	loc, spec, err := cutCronLocation(strings.TrimSpace(spec))
+	if err != nil {
+		return nil, err
+	}
+
+	if descriptor, ok := cronDescriptors[spec]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w: expected 5 or 6 fields, got %d in %q", ErrInvalidCronSpec, len(fields), spec)
+	}
+
+	s := &cronSchedule{loc: loc}
+	targets := []*uint64{&s.second, &s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+
+	for i, field := range fields {
+		bits, err := cronBounds[i].parse(field)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s field %q: %s", ErrInvalidCronSpec, cronBounds[i].name, field, err.Error())
+		}
+
+		*targets[i] = bits
+	}
+
+	// Sunday is both 0 and 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	s.domAny = isCronWildcard(fields[3])
+	s.dowAny = isCronWildcard(fields[5])
+
+	if !s.daysExist() {
+		return nil, fmt.Errorf("%w: day of month %q never matches month %q", ErrInvalidCronSpec, fields[3], fields[4])
+	}
+
+	return s, nil
+}
+
+// cutCronLocation returns the time zone of the CRON_TZ= (or TZ=) spec prefix, if any, and the rest of the spec
+func cutCronLocation(spec string) (*time.Location, string, error) {
+	if !strings.HasPrefix(spec, "CRON_TZ=") && !strings.HasPrefix(spec, "TZ=") {
+		return nil, spec, nil
+	}
+
+	tz, rest, _ := strings.Cut(spec, " ")
+	_, name, _ := strings.Cut(tz, "=")
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: time zone %s: %s", ErrInvalidCronSpec, name, err.Error())
+	}
+
+	return loc, strings.TrimSpace(rest), nil
+}
+
+//nolint:gochecknoglobals
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// cronBound is a cron field bounds
+type cronBound struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+//nolint:gochecknoglobals
+var cronBounds = []cronBound{
+	{name: "second", min: 0, max: 59},
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}},
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}},
+}
+
+// parse parses the field into the bitset of the allowed values
+func (b cronBound) parse(field string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		from, to, step, err := b.parsePart(part)
+		if err != nil {
+			return 0, err
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << v
+		}
+	}
+
+	return bits, nil
+}
+
+// parsePart parses the list part into the values range and the step
+func (b cronBound) parsePart(part string) (from int, to int, step int, err error) {
+	rng, stepStr, hasStep := strings.Cut(part, "/")
+	step = 1
+
+	if hasStep {
+		if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+			return 0, 0, 0, fmt.Errorf("invalid step %q", stepStr)
+		}
+	}
+
+	if from, to, err = b.parseRange(rng, hasStep); err != nil {
+		return 0, 0, 0, err
+	}
+
+	if from > to {
+		return 0, 0, 0, fmt.Errorf("invalid range %d-%d", from, to)
+	}
+
+	return from, to, step, nil
+}
+
+// parseRange parses the wildcard, the range or the single value,
+// the stepped single value is a start of the range up to the max value
+func (b cronBound) parseRange(rng string, hasStep bool) (from int, to int, err error) {
+	switch {
+	case rng == "*" || rng == "?":
+		return b.min, b.max, nil
+	case strings.Contains(rng, "-"):
+		fromStr, toStr, _ := strings.Cut(rng, "-")
+
+		if from, err = b.value(fromStr); err != nil {
+			return 0, 0, err
+		}
+
+		if to, err = b.value(toStr); err != nil {
+			return 0, 0, err
+		}
+
+		return from, to, nil
+	}
+
+	if from, err = b.value(rng); err != nil {
+		return 0, 0, err
+	}
+
+	if hasStep {
+		return from, b.max, nil
+	}
+
+	return from, from, nil
+}
+
+// value parses the single field value
+func (b cronBound) value(str string) (int, error) {
+	if val, ok := b.names[strings.ToUpper(str)]; ok {
+		return val, nil
+	}
+
+	val, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", str)
+	}
+
+	if val < b.min || val > b.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", val, b.min, b.max)
+	}
+
+	return val, nil
+}
+
+// isCronWildcard checks if the day field is a wildcard for the day of month and day of week combination.
+// As in the Vixie cron, any field starting with * (including the stepped */10) is a wildcard.
+func isCronWildcard(field string) bool {
+	return strings.HasPrefix(field, "*") || field == "?"
+}
+
+// cronSchedule is a Schedule parsed from the cron spec
+type cronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+
+	domAny bool
+	dowAny bool
+	loc    *time.Location
+}
+
+// cronSearchYears is a limit of the next activation time search
+const cronSearchYears = 5
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	if s.loc != nil {
+		t = t.In(s.loc)
+	}
+
+	loc := t.Location()
+	t = t.Truncate(time.Second).Add(time.Second)
+	limit := t.Year() + cronSearchYears
+
+	for t.Year() <= limit {
+		var next time.Time
+
+		switch {
+		case !hasBit(s.month, int(t.Month())):
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !hasBit(s.hour, t.Hour()):
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !hasBit(s.minute, t.Minute()):
+			next = t.Truncate(time.Minute).Add(time.Minute)
+		case !hasBit(s.second, t.Second()):
+			next = t.Add(time.Second)
+		default:
+			return t
+		}
+
+		// Guard against the DST transitions moving the time back
+		if !next.After(t) {
+			next = t.Add(time.Second)
+		}
+
+		t = next
+	}
+
+	return time.Time{}
+}
+
+// cronMonthDays are the max days of the months, February has 29 days in the leap years
+//
+//nolint:gochecknoglobals
+var cronMonthDays = [13]int{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// daysExist checks if any of the allowed days of month exists in any of the allowed months.
+// If both day fields are restricted, the day of week always matches some days.
+func (s *cronSchedule) daysExist() bool {
+	if !s.domAny && !s.dowAny {
+		return true
+	}
+
+	for month := 1; month <= 12; month++ {
+		if !hasBit(s.month, month) {
+			continue
+		}
+
+		for day := 1; day <= cronMonthDays[month]; day++ {
+			if hasBit(s.dom, day) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// dayMatches checks the day of month and day of week fields.
+// If both are restricted, the day matching any of them is allowed.
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := hasBit(s.dom, t.Day())
+	dowMatch := hasBit(s.dow, int(t.Weekday()))
+
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+func hasBit(bits uint64, n int) bool {
+	return bits&(1<<n) != 0
+}
@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	tests := []struct {
+		Spec     string
+		From     string
+		Expected string
+	}{
+		{"*/5 * * * *", "2024-03-10 10:02:30", "2024-03-10 10:05:00"},
+		{"0 9 * * MON-FRI", "2024-03-08 09:00:00", "2024-03-11 09:00:00"},
+		{"30 */10 * * * *", "2024-03-10 10:00:30", "2024-03-10 10:10:30"},
+		{"0 0 1,15 * *", "2024-03-02 00:00:00", "2024-03-15 00:00:00"},
+		{"0 0 13 * 5", "2024-03-10 00:00:00", "2024-03-13 00:00:00"},
+		{"0 12 29 2 *", "2024-03-01 00:00:00", "2028-02-29 12:00:00"},
+		{"0 0 * * 7", "2024-03-10 00:00:00", "2024-03-17 00:00:00"},
+		{"15-45/15 3 * JAN,dec ?", "2024-03-10 00:00:00", "2024-12-01 03:15:00"},
+		{"0 0 */10 * MON", "2024-03-30 00:00:00", "2024-04-01 00:00:00"},
+		{"0 0 31 4,5 *", "2024-03-30 00:00:00", "2024-05-31 00:00:00"},
+		{"@hourly", "2024-03-10 10:59:59", "2024-03-10 11:00:00"},
+		{"@monthly", "2024-12-10 00:00:00", "2025-01-01 00:00:00"},
+	}
+
+	for _, test := range tests {
+		schedule, err := service.ParseCron(test.Spec)
+		require.NoError(t, err, test.Spec)
+
+		from, _ := time.Parse(time.DateTime, test.From)
+		expected, _ := time.Parse(time.DateTime, test.Expected)
+
+		assert.Equal(t, expected, schedule.Next(from), test.Spec)
+	}
+}
+
+func TestParseCron_WhenTimeZone_ExpectNextInZone(t *testing.T) {
+	schedule, err := service.ParseCron("CRON_TZ=America/New_York 0 9 * * *")
+	require.NoError(t, err)
+
+	next := schedule.Next(time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestParseCron_WhenInvalid_ExpectError(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"CRON_TZ=Nowhere/Unknown * * * * *",
+		"0 0 31 4 *",
+		"0 0 30,31 2 *",
+		"0 0 31 4,6 */2",
+	}
+
+	for _, spec := range specs {
+		_, err := service.ParseCron(spec)
+
+		assert.ErrorIs(t, err, service.ErrInvalidCronSpec, spec)
+	}
+}
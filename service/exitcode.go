@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ExitCoder is an interface of the errors defining the Service exit code.
+// If the error returned by the Runner (or any error in its chain) implements it,
+// the Service exits with its code instead of the ExitCodes.RunnerFailed.
+//
+// When several runners fail, the exit code is resolved by the priority rule:
+// the ExitCoder's code wins over the generic RunnerFailed code,
+// the code of the first failed runner wins over the codes of the runners failed later.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// NewExitError returns the error with the exit code, wrapping the given one.
+func NewExitError(code int, err error) *ExitError {
+	return &ExitError{Code: code, Err: err}
+}
+
+// ExitError is an error with the Service exit code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error returns the error message.
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit code %d", e.Code)
+	}
+
+	return e.Err.Error()
+}
+
+// ExitCode returns the exit code.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// Unwrap returns the wrapped error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// exitCodeOf returns the exit code of the failed runner's error
+// and whether it is defined by the ExitCoder.
+func (s *Service) exitCodeOf(err error) (code int, explicit bool) {
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode(), true
+	}
+
+	return s.opt.exitCodes.RunnerFailed, false
+}
+
+// storeFailureExitCode stores the exit code of the failed runner according to the priority rule,
+// see ExitCoder.
+func (s *Service) storeFailureExitCode(err error) {
+	code, explicit := s.exitCodeOf(err)
+
+	s.failureMu.Lock()
+	defer s.failureMu.Unlock()
+
+	switch {
+	case s.failureCode == failureExplicit:
+		return
+	case s.failureCode == failureGeneric && !explicit:
+		return
+	}
+
+	s.failureCode = failureGeneric
+	if explicit {
+		s.failureCode = failureExplicit
+	}
+
+	s.exitCode.Store(int32(code))
+}
+
+// failureCodeKind is a kind of the failure exit code stored in the Service.
+type failureCodeKind int
+
+const (
+	failureNone failureCodeKind = iota
+	failureGeneric
+	failureExplicit
+)
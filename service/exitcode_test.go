@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type usageError struct{}
+
+func (e usageError) Error() string {
+	return "usage error"
+}
+
+func (e usageError) ExitCode() int {
+	return 64
+}
+
+type failingCmd struct {
+	err error
+}
+
+func (c *failingCmd) ExecuteContext(_ context.Context) error {
+	return c.err
+}
+
+func TestService_Run_WhenExitCoderReturned_ExpectItsCode(t *testing.T) {
+	tests := []struct {
+		Err          error
+		ExpectedCode int
+	}{
+		{usageError{}, 64},
+		{fmt.Errorf("wrapped: %w", usageError{}), 64},
+		{service.NewExitError(75, errors.New("temporary failure")), 75},
+		{errors.New("generic error"), service.DefaultExitCodes().RunnerFailed},
+	}
+
+	for i, test := range tests {
+		srv := getSrv()
+
+		srv.RegisterRunner(service.NewCommandRunner(&failingCmd{err: test.Err}))
+
+		assert.Equal(t, test.ExpectedCode, srv.Run(context.Background()), "test case #%d", i)
+	}
+}
+
+func TestService_Run_WhenSeveralFailed_ExpectPriorityRule(t *testing.T) {
+	tests := []struct {
+		First        error
+		Second       error
+		ExpectedCode int
+	}{
+		{errors.New("generic error"), service.NewExitError(75, nil), 75},
+		{service.NewExitError(64, nil), service.NewExitError(75, nil), 64},
+		{service.NewExitError(64, nil), errors.New("generic error"), 64},
+		{errors.New("generic error"), errors.New("generic error"), service.DefaultExitCodes().RunnerFailed},
+	}
+
+	for i, test := range tests {
+		srv := getSrv()
+		started := sync.WaitGroup{}
+		first, second := test.First, test.Second
+
+		started.Add(1)
+
+		srv.RegisterRunner(
+			service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+				started.Wait()
+
+				return first
+			}),
+			service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+				started.Done()
+				<-ctx.Done()
+
+				return second
+			}),
+		)
+
+		assert.Equal(t, test.ExpectedCode, srv.Run(context.Background()), "test case #%d", i)
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// Option is a Service option.
+type Option func(opt *options)
+
+// ExitCodes is a set of the Service exit codes.
+type ExitCodes struct {
+	// NoRunners is returned when no runners registered in the Service.
+	NoRunners int
+
+	// Canceled is returned when the Service context is canceled before the runner started.
+	Canceled int
+
+	// RunnerFailed is returned when the runner returned an error or panicked.
+	// Errors implementing the ExitCoder interface override it.
+	RunnerFailed int
+
+	// Signaled is returned when the Service is stopped by the OS signal.
+	// See WithSignalExitCodes to use the 128+signal number convention.
+	Signaled int
+
+	// Forced is returned when the Service exit is forced by the second signal.
+	Forced int
+
+	// ReloadFailed is returned when the reload failed and reload failures are fatal.
+	// See WithReloadFailureFatal.
+	ReloadFailed int
+
+	// Stopped is returned when the Service is stopped with the Stop method.
+	Stopped int
+}
+
+// DefaultExitCodes returns the default Service exit codes.
+func DefaultExitCodes() ExitCodes {
+	return ExitCodes{
+		NoRunners:    1,
+		Canceled:     2,
+		RunnerFailed: 3,
+		Signaled:     128,
+		Forced:       4,
+		ReloadFailed: 5,
+		Stopped:      0,
+	}
+}
+
+// WithSignals sets the OS signals stopping the Service.
+// Default signals are SIGINT and SIGTERM.
+func WithSignals(signals ...os.Signal) Option {
+	return func(opt *options) {
+		opt.stopSignals = signals
+	}
+}
+
+// WithoutSignals disables the OS signals handling:
+// the Service is stopped by the context cancellation or the Stop method only.
+// Use it to run several services in one process or in tests.
+func WithoutSignals() Option {
+	return func(opt *options) {
+		opt.signalsDisabled = true
+	}
+}
+
+// WithSignalSource sets the channel to receive the signals from instead of the OS.
+// Signals are handled as the OS signals (see WithSignals, WithReloadSignals and WithForwardedSignals),
+// but the process-global signals state is not touched.
+func WithSignalSource(source <-chan os.Signal) Option {
+	return func(opt *options) {
+		opt.signalSource = source
+	}
+}
+
+// WithReloadSignals sets the OS signals reloading the Service without stopping it.
+// On the reload signal, the Reload method is called
+// for the runners and the DI objects implementing the Reloadable interface.
+// Default signal is SIGHUP.
+func WithReloadSignals(signals ...os.Signal) Option {
+	return func(opt *options) {
+		opt.reloadSignals = signals
+	}
+}
+
+// WithReloadFailureFatal makes the reload failures stop the Service with the ReloadFailed exit code.
+// By default, reload failures are logged and counted only.
+func WithReloadFailureFatal() Option {
+	return func(opt *options) {
+		opt.reloadFailureFatal = true
+	}
+}
+
+// WithIgnoredSignals sets the OS signals ignored while the Service is running.
+func WithIgnoredSignals(signals ...os.Signal) Option {
+	return func(opt *options) {
+		opt.ignoredSignals = append(opt.ignoredSignals, signals...)
+	}
+}
+
+// WithForwardedSignals sets the OS signals passed to the handler function
+// without stopping the Service.
+func WithForwardedSignals(handler func(sig os.Signal), signals ...os.Signal) Option {
+	return func(opt *options) {
+		for _, sig := range signals {
+			opt.forwardedSignals[sig] = handler
+		}
+	}
+}
+
+// WithExitCodes sets the Service exit codes.
+func WithExitCodes(codes ExitCodes) Option {
+	return func(opt *options) {
+		opt.exitCodes = codes
+	}
+}
+
+// WithSignalExitCodes enables the 128+signal number exit code convention
+// when the Service is stopped by the OS signal.
+func WithSignalExitCodes() Option {
+	return func(opt *options) {
+		opt.signalExitCodes = true
+	}
+}
+
+// WithShutdownTimeout sets the maximum duration to wait for the runners to return
+// after the shutdown is started. When exceeded, the remaining runners are abandoned
+// and the container is not closed, since they may still use it.
+// Zero timeout (default) means waiting forever.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(opt *options) {
+		opt.shutdownTimeout = timeout
+	}
+}
+
+// DefaultStageTimeout is a default timeout of the startup stage readiness, see WithStageTimeout.
+const DefaultStageTimeout = time.Minute
+
+// WithStageTimeout sets the maximum duration to wait for the runners of the stage to become ready
+// before the next stage is started, see InStage. When exceeded, the runners not ready are logged
+// and the next stage is started anyway. Default is DefaultStageTimeout.
+//
+// Zero timeout means waiting forever: a staged runner never calling Ready (and not returning)
+// blocks all later stages, the runners not ready are logged periodically while waiting.
+func WithStageTimeout(timeout time.Duration) Option {
+	return func(opt *options) {
+		opt.stageTimeout = timeout
+	}
+}
+
+// DefaultStageLogInterval is a default interval of logging the runners not ready yet
+// while waiting for the stage, see WithStageLogInterval.
+const DefaultStageLogInterval = 10 * time.Second
+
+// WithStageLogInterval sets the interval of logging the runners not ready yet while waiting for the stage,
+// see InStage. Default is DefaultStageLogInterval, zero disables the periodic logging.
+func WithStageLogInterval(interval time.Duration) Option {
+	return func(opt *options) {
+		opt.stageLogInterval = interval
+	}
+}
+
+type options struct {
+	stopSignals        []os.Signal
+	reloadSignals      []os.Signal
+	ignoredSignals     []os.Signal
+	forwardedSignals   map[os.Signal]func(sig os.Signal)
+	exitCodes          ExitCodes
+	signalExitCodes    bool
+	shutdownTimeout    time.Duration
+	stageTimeout       time.Duration
+	stageLogInterval   time.Duration
+	reloadFailureFatal bool
+	signalsDisabled    bool
+	signalSource       <-chan os.Signal
+}
+
+func newOptions(opts []Option) *options {
+	opt := &options{
+		stopSignals:      []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+		reloadSignals:    []os.Signal{syscall.SIGHUP},
+		forwardedSignals: make(map[os.Signal]func(sig os.Signal)),
+		exitCodes:        DefaultExitCodes(),
+		stageTimeout:     DefaultStageTimeout,
+		stageLogInterval: DefaultStageLogInterval,
+	}
+
+	for _, fn := range opts {
+		fn(opt)
+	}
+
+	return opt
+}
+
+// signaledExitCode returns the exit code for the Service stopped by the signal.
+func (o *options) signaledExitCode(sig os.Signal) int {
+	if o.signalExitCodes {
+		if num, ok := sig.(syscall.Signal); ok {
+			return 128 + int(num)
+		}
+	}
+
+	return o.exitCodes.Signaled
+}
+
+// isReloadSignal checks if the signal reloads the Service.
+func (o *options) isReloadSignal(sig os.Signal) bool {
+	for _, reload := range o.reloadSignals {
+		if reload == sig {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"github.com/kukymbr/core2go/service/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestService_Run_WhenCustomExitCodes_ExpectCustomCode(t *testing.T) {
+	codes := service.DefaultExitCodes()
+	codes.NoRunners = 11
+	codes.RunnerFailed = 13
+
+	srv := service.New(&di.Container{}, zap.NewNop(), service.WithExitCodes(codes))
+	assert.Equal(t, 11, srv.Run(context.Background()))
+
+	srv = service.New(&di.Container{}, zap.NewNop(), service.WithExitCodes(codes))
+	srv.RegisterRunner(service.NewCommandRunner(&panickingCmd{}))
+	assert.Equal(t, 13, srv.Run(context.Background()))
+}
+
+func TestService_Run_WhenShutdownTimeout_ExpectRunnersAbandoned(t *testing.T) {
+	h := servicetest.New(t, nil, service.WithShutdownTimeout(100*time.Millisecond))
+	stuck := make(chan struct{})
+
+	defer close(stuck)
+
+	h.Register(
+		service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+			<-stuck
+
+			return nil
+		}),
+		service.NewRouterRunner(&service.NopRouter{}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result := h.Run(ctx)
+
+	assert.NotEqual(t, 0, result.ExitCode)
+	assert.False(t, result.Forced)
+	assert.Less(t, time.Since(start), time.Second)
+	h.AssertContainerNotClosed()
+
+	entries := h.Logs.FilterMessage("Shutdown timeout exceeded, abandoning runners, the container is not closed").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, []any{"#0"}, entries[0].ContextMap()["stuck_runners"])
+}
+
+func TestService_Run_WhenSignalAfterStop_ExpectNotForced(t *testing.T) {
+	h := servicetest.New(t, nil)
+	draining := make(chan struct{})
+	release := make(chan struct{})
+
+	h.Register(service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+		<-ctx.Done()
+		close(draining)
+		<-release
+
+		return nil
+	}))
+	h.Start(context.Background())
+	h.WaitStarted()
+
+	h.Service.Stop("test")
+	<-draining
+
+	h.Signal(syscall.SIGTERM)
+	close(release)
+
+	result := h.Wait()
+
+	assert.Equal(t, service.TriggerStopped, result.Trigger)
+	assert.Equal(t, 0, result.ExitCode)
+	assert.False(t, result.Forced)
+	assert.Equal(t, 1, h.Logs.FilterMessage("Got shutdown signal: terminated").Len())
+	h.AssertContainerClosed()
+}
+
+func TestService_Stop(t *testing.T) {
+	srv := service.New(&di.Container{}, zap.NewNop(), service.WithoutSignals())
+	results := make(chan service.RunResult, 1)
+
+	srv.RegisterRunner(service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+		srv.Stop("test stop")
+		srv.Stop("ignored")
+
+		<-ctx.Done()
+
+		return nil
+	}))
+
+	go func() {
+		results <- srv.RunWithResult(context.Background())
+	}()
+
+	select {
+	case <-srv.Done():
+	case <-time.After(time.Second):
+		require.Fail(t, "service is not stopped")
+	}
+
+	result := <-results
+
+	assert.Equal(t, service.TriggerStopped, result.Trigger)
+	assert.Equal(t, "test stop", result.Reason)
+	assert.Equal(t, service.DefaultExitCodes().Stopped, result.ExitCode)
+}
+
+func TestService_Stop_WhenCalledBeforeRun_ExpectStoppedOnStart(t *testing.T) {
+	srv := service.New(&di.Container{}, zap.NewNop(), service.WithoutSignals())
+
+	srv.RegisterRunner(service.NewRouterRunner(&service.NopRouter{}))
+	srv.Stop("before run")
+
+	result := srv.RunWithResult(context.Background())
+
+	assert.Equal(t, service.TriggerStopped, result.Trigger)
+	assert.Equal(t, "before run", result.Reason)
+}
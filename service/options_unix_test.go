@@ -0,0 +1,112 @@
+//go:build unix
+
+package service_test
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestService_Run_WhenSignaled_ExpectSignalExitCode(t *testing.T) {
+	forwarded := atomic.Int32{}
+
+	srv := service.New(
+		&di.Container{},
+		zap.NewNop(),
+		service.WithSignals(syscall.SIGUSR1),
+		service.WithForwardedSignals(func(sig os.Signal) {
+			forwarded.Add(1)
+		}, syscall.SIGUSR2),
+		service.WithSignalExitCodes(),
+	)
+
+	srv.RegisterRunner(service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+		_ = syscall.Kill(os.Getpid(), syscall.SIGUSR2)
+
+		assert.Eventually(t, func() bool {
+			return forwarded.Load() == 1
+		}, time.Second, 5*time.Millisecond)
+
+		_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+
+		<-ctx.Done()
+
+		return nil
+	}))
+
+	result := srv.RunWithResult(context.Background())
+
+	assert.Equal(t, 128+int(syscall.SIGUSR1), result.ExitCode)
+	assert.Equal(t, service.TriggerSignal, result.Trigger)
+	assert.Equal(t, syscall.SIGUSR1, result.Signal)
+	assert.True(t, result.Runners[0].Canceled)
+	assert.Equal(t, int32(1), forwarded.Load())
+}
+
+func TestService_Run_WhenSecondSignal_ExpectForcedExit(t *testing.T) {
+	srv := service.New(&di.Container{}, zap.NewNop(), service.WithSignals(syscall.SIGUSR1))
+	stuck := make(chan struct{})
+	started := make(chan struct{})
+
+	defer close(stuck)
+
+	srv.RegisterRunner(service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+		close(started)
+		<-stuck
+
+		return nil
+	}))
+
+	go func() {
+		<-started
+
+		_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+
+		time.Sleep(50 * time.Millisecond)
+
+		_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+	}()
+
+	code := srv.Run(context.Background())
+
+	assert.Equal(t, service.DefaultExitCodes().Forced, code)
+}
+
+func TestService_Run_WhenSignalSource_ExpectSignalsFromSource(t *testing.T) {
+	signals := make(chan os.Signal, 1)
+	forwarded := make(chan os.Signal, 1)
+
+	srv := service.New(
+		&di.Container{},
+		zap.NewNop(),
+		service.WithSignalSource(signals),
+		service.WithForwardedSignals(func(sig os.Signal) {
+			forwarded <- sig
+		}, syscall.SIGUSR1),
+	)
+
+	srv.RegisterRunner(service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+		signals <- syscall.SIGUSR1
+		assert.Equal(t, syscall.SIGUSR1, <-forwarded)
+
+		signals <- syscall.SIGTERM
+		<-ctx.Done()
+
+		return nil
+	}))
+
+	result := srv.RunWithResult(context.Background())
+
+	assert.Equal(t, service.TriggerSignal, result.Trigger)
+	assert.Equal(t, syscall.SIGTERM, result.Signal)
+	assert.Equal(t, service.DefaultExitCodes().Signaled, result.ExitCode)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+// Reloadable is an interface of the runners and DI objects supporting the reload,
+// for example, re-reading the configuration.
+// Reload is called on the reload signal (SIGHUP by default) without stopping the Service.
+type Reloadable interface {
+	Reload(ctx context.Context) error
+}
+
+// reload calls Reload for the reloadable runners and the container's objects.
+// Returns joined errors of the failed reloads.
+func (s *Service) reload(ctx context.Context) (err error) {
+	if !s.reloading.CompareAndSwap(false, true) {
+		s.log.Warn("Reload is already in progress, skipped")
+
+		return nil
+	}
+
+	defer s.reloading.Store(false)
+
+	s.log.Info("Reloading the Service")
+
+	reloaded := make([]any, 0)
+
+	for i, runner := range s.runners {
+		if r, ok := findRunner[Reloadable](runner); ok {
+			err = errors.Join(err, s.reloadOne(ctx, s.runnerName(i), r))
+			reloaded = append(reloaded, r)
+		}
+	}
+
+	if s.ctn != nil {
+		s.ctn.Range(func(name string, obj any) bool {
+			r, ok := obj.(Reloadable)
+			if !ok || containsObject(reloaded, obj) {
+				return true
+			}
+
+			err = errors.Join(err, s.reloadOne(ctx, name, r))
+
+			return true
+		})
+	}
+
+	s.reloads.Add(1)
+
+	return err
+}
+
+// reloadOne reloads the single object
+func (s *Service) reloadOne(ctx context.Context, name string, r Reloadable) error {
+	if err := r.Reload(ctx); err != nil {
+		s.reloadFailures.Add(1)
+		s.log.Error("reload failed", zap.String("name", name), zap.Error(err))
+
+		return fmt.Errorf("reload %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// containsObject checks if the list contains the same object
+func containsObject(list []any, obj any) bool {
+	for _, item := range list {
+		if sameObject(item, obj) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameObject checks if the objects are the same: pointers are compared by the address,
+// other values with ==. Functions and values not comparable in runtime
+// (like structs with the maps in the interface fields) are considered different.
+func sameObject(a any, b any) (same bool) {
+	typ := reflect.TypeOf(a)
+	if typ == nil || typ != reflect.TypeOf(b) || !typ.Comparable() {
+		return false
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+	}
+
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+
+	return a == b
+}
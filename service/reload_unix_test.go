@@ -0,0 +1,208 @@
+//go:build unix
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"github.com/kukymbr/core2go/service/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type reloadableRunner struct {
+	reloads atomic.Int32
+	err     error
+}
+
+func (r *reloadableRunner) Run(ctx context.Context, _ *di.Container) error {
+	_ = syscall.Kill(os.Getpid(), syscall.SIGHUP)
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func (r *reloadableRunner) Reload(_ context.Context) error {
+	r.reloads.Add(1)
+
+	return r.err
+}
+
+// valueReloadable is comparable by type, but panics on == with a map in the data field
+type valueReloadable struct {
+	reloads *atomic.Int32
+	data    any
+}
+
+func (r valueReloadable) Run(ctx context.Context, _ *di.Container) error {
+	<-ctx.Done()
+
+	return nil
+}
+
+func (r valueReloadable) Reload(_ context.Context) error {
+	r.reloads.Add(1)
+
+	return nil
+}
+
+func TestService_Run_WhenReloadableNotComparable_ExpectReloaded(t *testing.T) {
+	runner := valueReloadable{reloads: &atomic.Int32{}, data: map[string]int{}}
+	builder := &di.Builder{}
+
+	err := builder.Add(di.Def{
+		Name: "runner",
+		Build: func(ctn *di.Container) (any, error) {
+			return runner, nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	h := servicetest.New(t, ctn)
+
+	h.Register(runner)
+	h.Start(context.Background())
+	h.WaitStarted()
+	h.Signal(syscall.SIGHUP)
+
+	require.Eventually(t, func() bool {
+		return h.Service.Vars().Reloads == 1
+	}, servicetest.DefaultTimeout, time.Millisecond)
+
+	h.Shutdown("test")
+
+	assert.Equal(t, int32(2), runner.reloads.Load())
+	assert.Equal(t, 0, h.Service.Vars().ReloadFailures)
+}
+
+func TestService_Run_WhenReloadSignal_ExpectReloaded(t *testing.T) {
+	runner := &reloadableRunner{}
+	obj := &reloadableRunner{}
+	builder := &di.Builder{}
+
+	err := builder.Add(
+		di.Def{
+			Name: "reloadable",
+			Build: func(ctn *di.Container) (any, error) {
+				return obj, nil
+			},
+		},
+		di.Def{
+			Name: "runner",
+			Build: func(ctn *di.Container) (any, error) {
+				return runner, nil
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	srv := service.New(ctn, zap.NewNop())
+	srv.RegisterRunner(runner)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		assert.Eventually(t, func() bool {
+			return srv.Vars().Reloads == 1
+		}, time.Second, 5*time.Millisecond)
+
+		cancel()
+	}()
+
+	srv.Run(ctx)
+
+	assert.Equal(t, int32(1), runner.reloads.Load())
+	assert.Equal(t, int32(1), obj.reloads.Load())
+	assert.Equal(t, 0, srv.Vars().ReloadFailures)
+}
+
+// blockingReloadable blocks the Reload until released
+type blockingReloadable struct {
+	started  chan struct{}
+	release  chan struct{}
+	reloaded atomic.Bool
+}
+
+func (r *blockingReloadable) Reload(_ context.Context) error {
+	close(r.started)
+	<-r.release
+	r.reloaded.Store(true)
+
+	return nil
+}
+
+func TestService_Run_WhenStoppedDuringReload_ExpectContainerClosedAfterReload(t *testing.T) {
+	obj := &blockingReloadable{started: make(chan struct{}), release: make(chan struct{})}
+	closedAfterReload := atomic.Bool{}
+	builder := &di.Builder{}
+
+	err := builder.Add(di.Def{
+		Name: "reloadable",
+		Build: func(ctn *di.Container) (any, error) {
+			return obj, nil
+		},
+		Close: func(_ any) error {
+			closedAfterReload.Store(obj.reloaded.Load())
+
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctn, err := builder.Build()
+	require.NoError(t, err)
+
+	h := servicetest.New(t, ctn)
+
+	h.Register(service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+		<-ctx.Done()
+
+		return nil
+	}))
+	h.Start(context.Background())
+	h.WaitStarted()
+	h.Signal(syscall.SIGHUP)
+
+	<-obj.started
+
+	h.Service.Stop("test")
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(obj.release)
+	}()
+
+	h.Wait()
+
+	h.AssertContainerClosed()
+	assert.True(t, closedAfterReload.Load())
+}
+
+func TestService_Run_WhenReloadFailedAndFatal_ExpectStopped(t *testing.T) {
+	runner := &reloadableRunner{err: errors.New("reload error")}
+
+	srv := service.New(&di.Container{}, zap.NewNop(), service.WithReloadFailureFatal())
+	srv.RegisterRunner(runner)
+
+	code := srv.Run(context.Background())
+
+	assert.Equal(t, service.DefaultExitCodes().ReloadFailed, code)
+	assert.Equal(t, 1, srv.Vars().ReloadFailures)
+}
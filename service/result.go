@@ -0,0 +1,168 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+)
+
+// Trigger is a reason the Service stopped.
+type Trigger int
+
+// Service stop triggers
+const (
+	// TriggerCompleted means all runners are done.
+	TriggerCompleted Trigger = iota + 1
+
+	// TriggerSignal means the Service is stopped by the OS signal.
+	TriggerSignal
+
+	// TriggerRunnerFailed means the runner returned an error or panicked.
+	TriggerRunnerFailed
+
+	// TriggerCanceled means the Service context is canceled.
+	TriggerCanceled
+
+	// TriggerNoRunners means no runners registered in the Service.
+	TriggerNoRunners
+
+	// TriggerReloadFailed means the reload failed and reload failures are fatal.
+	TriggerReloadFailed
+
+	// TriggerStopped means the Service is stopped with the Stop method.
+	TriggerStopped
+)
+
+// String returns the trigger name.
+func (t Trigger) String() string {
+	switch t {
+	case TriggerCompleted:
+		return "completed"
+	case TriggerSignal:
+		return "signal"
+	case TriggerRunnerFailed:
+		return "runner failed"
+	case TriggerCanceled:
+		return "canceled"
+	case TriggerNoRunners:
+		return "no runners"
+	case TriggerReloadFailed:
+		return "reload failed"
+	case TriggerStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// RunnerResult is an outcome of the Runner execution.
+type RunnerResult struct {
+	// Name is the Runner's name, see NamedRunner.
+	Name string
+
+	// Role is the Runner's role, see RoleRunner.
+	Role Role
+
+	// Status is the Runner's final status.
+	// RunnerRunning means the runner is abandoned after the shutdown timeout.
+	Status RunnerStatus
+
+	// Err is an error returned by the Runner.
+	Err error
+
+	// Panic is a value recovered from the Runner's panic.
+	Panic any
+
+	// PanicStack is a stack trace of the Runner's panic.
+	PanicStack []byte
+
+	// Duration is the Runner execution time.
+	Duration time.Duration
+
+	// Canceled is true if the Runner returned after the Service context is canceled
+	// or didn't start because of it.
+	Canceled bool
+}
+
+// RunResult is an outcome of the Service execution.
+type RunResult struct {
+	// Trigger is a reason the Service stopped.
+	Trigger Trigger
+
+	// Signal is the OS signal stopped the Service, if the Trigger is TriggerSignal.
+	Signal os.Signal
+
+	// Reason is the reason passed to the Stop method, if the Trigger is TriggerStopped.
+	Reason string
+
+	// ExitCode is the Service exit code.
+	ExitCode int
+
+	// Forced is true if the exit is forced by the second signal.
+	Forced bool
+
+	// Runners are the runners outcomes in the registration order.
+	Runners []RunnerResult
+}
+
+// Err returns the joined errors of all failed runners, nil if none failed.
+func (r RunResult) Err() error {
+	errs := make([]error, 0)
+
+	for _, runner := range r.Runners {
+		if runner.Status == RunnerFailed {
+			errs = append(errs, runner.Err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// panicError is an error of the panicked Runner.
+type panicError struct {
+	name  string
+	value any
+	stack []byte
+}
+
+// newPanicError creates the panicError, must be called from the deferred function recovering the panic
+// to capture the stack trace of the panicked goroutine.
+func newPanicError(name string, value any) *panicError {
+	return &panicError{name: name, value: value, stack: debug.Stack()}
+}
+
+func (e *panicError) Error() string {
+	if e.name == "" {
+		return fmt.Sprintf("runner panicked: %v", e.value)
+	}
+
+	return fmt.Sprintf("runner %s panicked: %v", e.name, e.value)
+}
+
+// result returns the Service execution outcome.
+func (s *Service) result(forced bool) RunResult {
+	result := RunResult{
+		Trigger:  Trigger(s.trigger.Load()),
+		Signal:   s.signal,
+		ExitCode: int(s.exitCode.Load()),
+		Forced:   forced,
+		Runners:  make([]RunnerResult, 0, len(s.states)),
+	}
+
+	if result.Trigger == TriggerStopped {
+		result.Reason = s.stopReason
+	}
+
+	for _, state := range s.states {
+		result.Runners = append(result.Runners, state.result())
+	}
+
+	return result
+}
+
+// setTrigger sets the stop trigger if it's not set yet, returns true if set.
+func (s *Service) setTrigger(trigger Trigger) bool {
+	return s.trigger.CompareAndSwap(0, int32(trigger))
+}
@@ -0,0 +1,117 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RunWithResult_WhenCompleted_ExpectResults(t *testing.T) {
+	srv := getSrv()
+
+	srv.RegisterRunner(
+		service.Named("first", service.NewCommandRunner(&service.NopCommand{})),
+		service.NewCommandRunner(&service.NopCommand{}),
+	)
+
+	result := srv.RunWithResult(context.Background())
+
+	assert.Equal(t, service.TriggerCompleted, result.Trigger)
+	assert.Equal(t, 0, result.ExitCode)
+	assert.False(t, result.Forced)
+	assert.NoError(t, result.Err())
+
+	require.Len(t, result.Runners, 2)
+	assert.Equal(t, "first", result.Runners[0].Name)
+	assert.Equal(t, "#1", result.Runners[1].Name)
+
+	for _, runner := range result.Runners {
+		assert.Equal(t, service.RunnerDone, runner.Status)
+		assert.NoError(t, runner.Err)
+		assert.Nil(t, runner.Panic)
+	}
+}
+
+func TestService_RunWithResult_WhenSeveralFailed_ExpectAllErrors(t *testing.T) {
+	srv := getSrv()
+	errFirst := errors.New("first error")
+	errShutdown := errors.New("shutdown error")
+	started := sync.WaitGroup{}
+
+	started.Add(2)
+
+	srv.RegisterRunner(
+		service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+			started.Wait()
+			time.Sleep(10 * time.Millisecond)
+
+			return errFirst
+		}),
+		service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+			started.Done()
+			<-ctx.Done()
+
+			return errShutdown
+		}),
+		service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+			started.Done()
+			<-ctx.Done()
+
+			panic("test panic")
+		}),
+	)
+
+	result := srv.RunWithResult(context.Background())
+
+	assert.Equal(t, service.DefaultExitCodes().RunnerFailed, result.ExitCode)
+	assert.Equal(t, service.TriggerRunnerFailed, result.Trigger)
+
+	require.Len(t, result.Runners, 3)
+
+	for _, runner := range result.Runners {
+		assert.Equal(t, service.RunnerFailed, runner.Status)
+	}
+
+	assert.ErrorIs(t, result.Runners[0].Err, errFirst)
+	assert.GreaterOrEqual(t, result.Runners[0].Duration, 10*time.Millisecond)
+	assert.ErrorIs(t, result.Runners[1].Err, errShutdown)
+	assert.True(t, result.Runners[1].Canceled)
+	assert.Equal(t, "test panic", result.Runners[2].Panic)
+
+	err := result.Err()
+	assert.ErrorIs(t, err, errFirst)
+	assert.ErrorIs(t, err, errShutdown)
+}
+
+func TestService_RunWithResult_WhenCanceled_ExpectCanceledTrigger(t *testing.T) {
+	srv := getSrv()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	srv.RegisterRunner(service.NewCommandRunner(&service.NopCommand{}))
+
+	result := srv.RunWithResult(ctx)
+
+	assert.Equal(t, service.TriggerCanceled, result.Trigger)
+	assert.Equal(t, service.DefaultExitCodes().Canceled, result.ExitCode)
+
+	require.Len(t, result.Runners, 1)
+	assert.Equal(t, service.RunnerCanceled, result.Runners[0].Status)
+	assert.True(t, result.Runners[0].Canceled)
+}
+
+func TestService_RunWithResult_WhenNoRunners_ExpectNoRunnersTrigger(t *testing.T) {
+	result := getSrv().RunWithResult(context.Background())
+
+	assert.Equal(t, service.TriggerNoRunners, result.Trigger)
+	assert.Equal(t, service.DefaultExitCodes().NoRunners, result.ExitCode)
+	assert.Empty(t, result.Runners)
+}
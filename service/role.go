@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+
+	"github.com/kukymbr/core2go/di"
+)
+
+// Role defines how the Runner's failure and completion affect the Service.
+type Role int
+
+// Runner roles
+const (
+	// RoleCritical is a default role: the Runner's failure stops the Service,
+	// the Service is completed when all critical runners are done.
+	// If no critical runners registered, the Service is completed when all runners are returned.
+	RoleCritical Role = iota
+
+	// RoleOptional is a role of the auxiliary Runner:
+	// its failure is logged and ignored, the Service doesn't wait for its completion
+	// unless no critical runners registered.
+	RoleOptional
+
+	// RoleTask is a role of the one-shot Runner:
+	// its failure stops the Service, the Service doesn't wait for its completion
+	// unless no critical runners registered.
+	RoleTask
+
+	// RoleDaemon is a role of the Runner the Service lives with:
+	// its failure or completion stops the Service.
+	RoleDaemon
+)
+
+// String returns the role name.
+func (r Role) String() string {
+	switch r {
+	case RoleCritical:
+		return "critical"
+	case RoleOptional:
+		return "optional"
+	case RoleTask:
+		return "task"
+	case RoleDaemon:
+		return "daemon"
+	default:
+		return "unknown"
+	}
+}
+
+// RoleRunner is a Runner with a role.
+// Runners not implementing it are critical.
+type RoleRunner interface {
+	Runner
+
+	// Role returns the Runner's role.
+	Role() Role
+}
+
+// Critical returns the Runner with the RoleCritical role.
+func Critical(runner Runner) RoleRunner {
+	return &roleRunner{role: RoleCritical, runner: runner}
+}
+
+// Optional returns the Runner with the RoleOptional role.
+func Optional(runner Runner) RoleRunner {
+	return &roleRunner{role: RoleOptional, runner: runner}
+}
+
+// Task returns the Runner with the RoleTask role.
+func Task(runner Runner) RoleRunner {
+	return &roleRunner{role: RoleTask, runner: runner}
+}
+
+// Daemon returns the Runner with the RoleDaemon role.
+func Daemon(runner Runner) RoleRunner {
+	return &roleRunner{role: RoleDaemon, runner: runner}
+}
+
+type roleRunner struct {
+	role   Role
+	runner Runner
+}
+
+func (r *roleRunner) Run(ctx context.Context, ctn *di.Container) error {
+	return r.runner.Run(ctx, ctn)
+}
+
+// Role returns the Runner's role.
+func (r *roleRunner) Role() Role {
+	return r.role
+}
+
+// Unwrap returns the Runner with the role.
+func (r *roleRunner) Unwrap() Runner {
+	return r.runner
+}
+
+// roleOf returns the runner's role if it's a RoleRunner or wraps one, RoleCritical otherwise.
+func roleOf(runner Runner) Role {
+	if r, ok := findRunner[RoleRunner](runner); ok {
+		return r.Role()
+	}
+
+	return RoleCritical
+}
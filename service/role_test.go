@@ -0,0 +1,150 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"github.com/kukymbr/core2go/service/servicetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func returningRunner(err error) service.Runner {
+	return service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+		return err
+	})
+}
+
+func blockingRunner() service.Runner {
+	return service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+		<-ctx.Done()
+
+		return nil
+	})
+}
+
+func TestService_Run_WhenOptionalFailed_ExpectIgnored(t *testing.T) {
+	h := servicetest.New(t, nil)
+
+	h.Register(
+		service.Named("optional", service.Optional(returningRunner(errors.New("optional error")))),
+		service.Named("critical", blockingRunner()),
+	)
+	h.Start(context.Background())
+	h.WaitStarted()
+
+	require.Eventually(t, func() bool {
+		return h.Service.Vars().Runners[0].Status == service.RunnerFailed.String()
+	}, servicetest.DefaultTimeout, time.Millisecond)
+
+	result := h.Shutdown("test")
+
+	assert.Equal(t, service.TriggerStopped, result.Trigger)
+	assert.Equal(t, 0, result.ExitCode)
+	assert.Equal(t, service.RoleOptional, result.Runners[0].Role)
+	assert.Equal(t, "optional", h.Service.Vars().Runners[0].Name)
+	assert.Equal(t, "optional", h.Service.Vars().Runners[0].Role)
+}
+
+func TestService_Run_WhenTaskDone_ExpectServiceAlive(t *testing.T) {
+	h := servicetest.New(t, nil)
+
+	h.Register(
+		service.Named("migration", service.Task(returningRunner(nil))),
+		service.Named("metrics", service.Daemon(blockingRunner())),
+	)
+	h.Start(context.Background())
+	h.WaitStarted()
+
+	require.Eventually(t, func() bool {
+		return len(h.Finished()) == 1
+	}, servicetest.DefaultTimeout, time.Millisecond)
+
+	h.AssertFinishOrder("migration")
+
+	result := h.Shutdown("test")
+
+	assert.Equal(t, service.TriggerStopped, result.Trigger)
+}
+
+func TestService_Run_WhenTaskFailed_ExpectStopped(t *testing.T) {
+	h := servicetest.New(t, nil)
+
+	h.Register(service.Task(returningRunner(errors.New("task error"))), blockingRunner())
+
+	result := h.Run(context.Background())
+
+	assert.Equal(t, service.TriggerRunnerFailed, result.Trigger)
+	assert.Equal(t, service.DefaultExitCodes().RunnerFailed, result.ExitCode)
+}
+
+func TestService_Run_WhenDaemonDone_ExpectCompleted(t *testing.T) {
+	h := servicetest.New(t, nil)
+	started := make(chan struct{})
+
+	h.Register(
+		service.Named("daemon", service.Daemon(service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+			<-started
+
+			return nil
+		}))),
+		service.Named("critical", service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+			close(started)
+			<-ctx.Done()
+
+			return nil
+		})),
+	)
+
+	result := h.Run(context.Background())
+
+	assert.Equal(t, service.TriggerCompleted, result.Trigger)
+	assert.Equal(t, 0, result.ExitCode)
+	h.AssertFinishOrder("daemon", "critical")
+}
+
+func TestService_Run_WhenCriticalDone_ExpectOthersNotAwaited(t *testing.T) {
+	h := servicetest.New(t, nil)
+
+	h.Register(
+		service.Critical(returningRunner(nil)),
+		service.Optional(blockingRunner()),
+		service.Task(blockingRunner()),
+	)
+
+	result := h.Run(context.Background())
+
+	assert.Equal(t, service.TriggerCompleted, result.Trigger)
+	assert.Equal(t, 0, result.ExitCode)
+}
+
+func TestService_Run_WhenNoCritical_ExpectAllAwaited(t *testing.T) {
+	h := servicetest.New(t, nil)
+	optionalDone := make(chan struct{})
+
+	h.Register(
+		service.Named("optional", service.Optional(service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+			defer close(optionalDone)
+
+			return errors.New("optional error")
+		}))),
+		service.Named("task", service.Task(service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+			<-optionalDone
+			time.Sleep(10 * time.Millisecond)
+
+			return nil
+		}))),
+	)
+
+	result := h.Run(context.Background())
+
+	assert.Equal(t, service.TriggerCompleted, result.Trigger)
+	assert.Equal(t, 0, result.ExitCode)
+	assert.Equal(t, service.RunnerDone, result.Runners[1].Status)
+	assert.False(t, result.Runners[1].Canceled)
+	h.AssertFinishOrder("optional", "task")
+}
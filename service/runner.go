@@ -12,3 +12,55 @@ type Runner interface {
 	// Run runs the Runner.
 	Run(ctx context.Context, ctn *di.Container) error
 }
+
+// NamedRunner is a Runner with a name.
+// The name is used in the Service logs, errors and state instead of the runner's index.
+type NamedRunner interface {
+	Runner
+
+	// Name returns the Runner's name.
+	Name() string
+}
+
+// Named returns the Runner with the given name, see NamedRunner.
+func Named(name string, runner Runner) NamedRunner {
+	return &namedRunner{name: name, runner: runner}
+}
+
+type namedRunner struct {
+	name   string
+	runner Runner
+}
+
+func (r *namedRunner) Run(ctx context.Context, ctn *di.Container) error {
+	return r.runner.Run(ctx, ctn)
+}
+
+// Name returns the Runner's name.
+func (r *namedRunner) Name() string {
+	return r.name
+}
+
+// Unwrap returns the named Runner.
+func (r *namedRunner) Unwrap() Runner {
+	return r.runner
+}
+
+// findRunner looks for the Runner implementing T in the chain of the wrapped runners
+// (see Supervise and Named), starting from the given one.
+func findRunner[T any](runner Runner) (found T, ok bool) {
+	for runner != nil {
+		if found, ok = runner.(T); ok {
+			return found, true
+		}
+
+		wrapper, isWrapper := runner.(interface{ Unwrap() Runner })
+		if !isWrapper {
+			break
+		}
+
+		runner = wrapper.Unwrap()
+	}
+
+	return found, false
+}
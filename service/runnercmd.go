@@ -14,8 +14,11 @@ type Command interface {
 }
 
 // NewCommandRunner creates new CLI command Service Runner.
+// The Runner reports ready (see Ready) when the command is started.
 func NewCommandRunner(command Command) Runner {
 	return NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+		Ready(ctx)
+
 		if err := command.ExecuteContext(ctx); err != nil {
 			return fmt.Errorf("command execute: %w", err)
 		}
@@ -10,6 +10,7 @@ import (
 type RunnerRunFn func(ctx context.Context, ctn *di.Container) error
 
 // NewCustomRunner returns a Runner executing the custom function.
+// The function reports its readiness by calling Ready, see InStage.
 func NewCustomRunner(fn RunnerRunFn) Runner {
 	return &customRunner{fn: fn}
 }
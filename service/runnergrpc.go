@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+)
+
+// DefaultGRPCStopTimeout is a default timeout of the gRPC server graceful stop.
+const DefaultGRPCStopTimeout = 10 * time.Second
+
+// grpcServerStoppedMsg is a message of the grpc.ErrServerStopped,
+// matched by the message to avoid the grpc dependency.
+const grpcServerStoppedMsg = "grpc: the server has been stopped"
+
+// GRPCServer is a gRPC server interface.
+// The *grpc.Server fits, see: https://pkg.go.dev/google.golang.org/grpc#Server
+type GRPCServer interface {
+	Serve(listener net.Listener) error
+	GracefulStop()
+	Stop()
+}
+
+// GRPCOption is an option of the gRPC server Runner.
+type GRPCOption func(r *grpcRunner)
+
+// WithGRPCStopTimeout sets the maximum duration to wait for the graceful stop of the server.
+// When exceeded, the server is stopped forcibly. Zero timeout means waiting forever.
+// Default is DefaultGRPCStopTimeout.
+func WithGRPCStopTimeout(timeout time.Duration) GRPCOption {
+	return func(r *grpcRunner) {
+		r.stopTimeout = timeout
+	}
+}
+
+// WithGRPCStoppedError sets the error returned by the Serve if the server is stopped before serving,
+// it's treated as a clean shutdown after the context is canceled.
+// By default, the grpc.ErrServerStopped of the *grpc.Server is matched by its message.
+func WithGRPCStoppedError(err error) GRPCOption {
+	return func(r *grpcRunner) {
+		r.stoppedErr = err
+	}
+}
+
+// NewGRPCRunner creates new Runner serving the gRPC server on the listener.
+// The server is stopped gracefully when the context is canceled.
+// If the server is stopped before serving (see WithGRPCStoppedError), it's a clean shutdown,
+// other Serve errors are returned.
+// The Runner reports ready (see Ready) when the serving is started.
+func NewGRPCRunner(server GRPCServer, listener net.Listener, opts ...GRPCOption) Runner {
+	r := &grpcRunner{
+		server:      server,
+		listener:    listener,
+		stopTimeout: DefaultGRPCStopTimeout,
+	}
+
+	for _, fn := range opts {
+		fn(r)
+	}
+
+	return r
+}
+
+type grpcRunner struct {
+	server      GRPCServer
+	listener    net.Listener
+	stopTimeout time.Duration
+	stoppedErr  error
+}
+
+func (r *grpcRunner) Run(ctx context.Context, _ *di.Container) error {
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- r.server.Serve(r.listener)
+	}()
+
+	LoggerFromContext(ctx).Info("Serving gRPC on " + r.listener.Addr().String())
+	Ready(ctx)
+
+	select {
+	case err := <-serveErr:
+		return r.serveError(ctx, err)
+	case <-ctx.Done():
+	}
+
+	r.stop(ctx)
+
+	return r.serveError(ctx, <-serveErr)
+}
+
+// serveError returns the Serve error, unless it's caused by the server stop on the context cancellation:
+// the Serve is called asynchronously, so it may be started after the stop.
+func (r *grpcRunner) serveError(ctx context.Context, err error) error {
+	if err == nil || (ctx.Err() != nil && r.isStopped(err)) {
+		return nil
+	}
+
+	return fmt.Errorf("serve grpc: %w", err)
+}
+
+// isStopped checks if the Serve error is caused by the server stop before serving
+func (r *grpcRunner) isStopped(err error) bool {
+	if r.stoppedErr != nil {
+		return errors.Is(err, r.stoppedErr)
+	}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		if err.Error() == grpcServerStoppedMsg {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stop stops the server gracefully, stops it forcibly if the stop timeout exceeded.
+func (r *grpcRunner) stop(ctx context.Context) {
+	var deadline <-chan time.Time
+
+	stopped := make(chan struct{})
+
+	go func() {
+		r.server.GracefulStop()
+		close(stopped)
+	}()
+
+	if r.stopTimeout > 0 {
+		timer := time.NewTimer(r.stopTimeout)
+		defer timer.Stop()
+
+		deadline = timer.C
+	}
+
+	select {
+	case <-stopped:
+	case <-deadline:
+		LoggerFromContext(ctx).Warn("gRPC server graceful stop timeout exceeded, stopping")
+
+		r.server.Stop()
+		<-stopped
+	}
+}
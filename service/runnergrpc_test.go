@@ -0,0 +1,221 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// errFakeServerStopped has the message of the grpc.ErrServerStopped
+	errFakeServerStopped = errors.New("grpc: the server has been stopped")
+	errFakeTransport     = errors.New("transport failure")
+)
+
+// fakeGRPCServer serves until stopped, the graceful stop waits for the active calls.
+// Like the *grpc.Server, it returns an error if served after the stop.
+type fakeGRPCServer struct {
+	active   sync.WaitGroup
+	stopped  chan struct{}
+	stopOnce sync.Once
+	forced   atomic.Bool
+	serving  chan struct{}
+	release  chan struct{}
+
+	// stoppedErr is returned if served after the stop
+	stoppedErr error
+
+	// err is returned after the stop
+	err error
+}
+
+func newFakeGRPCServer() *fakeGRPCServer {
+	return &fakeGRPCServer{
+		stopped:    make(chan struct{}),
+		serving:    make(chan struct{}),
+		stoppedErr: errFakeServerStopped,
+	}
+}
+
+func (s *fakeGRPCServer) Serve(_ net.Listener) error {
+	if s.release != nil {
+		<-s.release
+	}
+
+	select {
+	case <-s.stopped:
+		return s.stoppedErr
+	default:
+	}
+
+	close(s.serving)
+	<-s.stopped
+
+	return s.err
+}
+
+func (s *fakeGRPCServer) GracefulStop() {
+	s.active.Wait()
+	s.stopOnce.Do(func() { close(s.stopped) })
+}
+
+func (s *fakeGRPCServer) Stop() {
+	s.forced.Store(true)
+	s.stopOnce.Do(func() { close(s.stopped) })
+	s.active.Done()
+}
+
+func TestGRPCRunner_WhenCanceled_ExpectGracefulStop(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = listener.Close() }()
+
+	server := newFakeGRPCServer()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-server.serving
+		cancel()
+	}()
+
+	err = service.NewGRPCRunner(server, listener).Run(ctx, nil)
+
+	assert.NoError(t, err)
+	assert.False(t, server.forced.Load())
+}
+
+func TestGRPCRunner_WhenCanceledBeforeServe_ExpectNoError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = listener.Close() }()
+
+	server := newFakeGRPCServer()
+	server.release = make(chan struct{})
+
+	go func() {
+		<-server.stopped
+		close(server.release)
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = service.NewGRPCRunner(server, listener).Run(ctx, nil)
+
+	assert.NoError(t, err)
+}
+
+func TestGRPCRunner_WhenServeFailed_ExpectError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = listener.Close() }()
+
+	server := newFakeGRPCServer()
+	close(server.stopped)
+
+	err = service.NewGRPCRunner(server, listener).Run(context.Background(), nil)
+
+	assert.ErrorIs(t, err, errFakeServerStopped)
+}
+
+func TestGRPCRunner_WhenStopTimeout_ExpectForcedStop(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = listener.Close() }()
+
+	server := newFakeGRPCServer()
+	server.active.Add(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-server.serving
+		cancel()
+	}()
+
+	start := time.Now()
+	err = service.NewGRPCRunner(server, listener, service.WithGRPCStopTimeout(50*time.Millisecond)).Run(ctx, nil)
+
+	assert.NoError(t, err)
+	assert.True(t, server.forced.Load())
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestGRPCRunner_WhenCanceledBeforeServeWithCustomError_ExpectNoError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = listener.Close() }()
+
+	server := newFakeGRPCServer()
+	server.stoppedErr = errFakeTransport
+	server.release = make(chan struct{})
+
+	go func() {
+		<-server.stopped
+		close(server.release)
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = service.NewGRPCRunner(server, listener, service.WithGRPCStoppedError(errFakeTransport)).Run(ctx, nil)
+
+	assert.NoError(t, err)
+}
+
+func TestGRPCRunner_WhenServeFailedOnShutdown_ExpectError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = listener.Close() }()
+
+	server := newFakeGRPCServer()
+	server.err = errFakeTransport
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-server.serving
+		cancel()
+	}()
+
+	err = service.NewGRPCRunner(server, listener).Run(ctx, nil)
+
+	assert.ErrorIs(t, err, errFakeTransport)
+}
+
+func TestGRPCRunner_WhenFailedToServeOnShutdown_ExpectError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = listener.Close() }()
+
+	server := newFakeGRPCServer()
+	server.stoppedErr = errFakeTransport
+	server.release = make(chan struct{})
+
+	go func() {
+		<-server.stopped
+		close(server.release)
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = service.NewGRPCRunner(server, listener).Run(ctx, nil)
+
+	assert.ErrorIs(t, err, errFakeTransport)
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"go.uber.org/zap"
+)
+
+// Default HTTP server runner options.
+const (
+	DefaultHTTPDrainTimeout      = 10 * time.Second
+	DefaultHTTPCertCheckInterval = 10 * time.Second
+)
+
+// HTTPServerOption is an option of the HTTP server Runner.
+type HTTPServerOption func(r *httpServerRunner)
+
+// WithHTTPListener sets the pre-bound listener to serve on instead of listening the server's address.
+func WithHTTPListener(listener net.Listener) HTTPServerOption {
+	return func(r *httpServerRunner) {
+		r.listener = listener
+	}
+}
+
+// WithHTTPDrainTimeout sets the maximum duration to wait for the active connections
+// to finish on the shutdown. When exceeded, the server is closed forcibly.
+// Default is DefaultHTTPDrainTimeout.
+func WithHTTPDrainTimeout(timeout time.Duration) HTTPServerOption {
+	return func(r *httpServerRunner) {
+		r.drainTimeout = timeout
+	}
+}
+
+// WithHTTPTLS enables TLS with the certificate and key files.
+// Files are reloaded when changed (see WithHTTPCertCheckInterval) and on the Service reload.
+func WithHTTPTLS(certFile string, keyFile string) HTTPServerOption {
+	return func(r *httpServerRunner) {
+		r.certs = &certReloader{certFile: certFile, keyFile: keyFile}
+	}
+}
+
+// WithHTTPCertCheckInterval sets the minimum interval between the TLS certificate files changes checks.
+// Default is DefaultHTTPCertCheckInterval.
+func WithHTTPCertCheckInterval(interval time.Duration) HTTPServerOption {
+	return func(r *httpServerRunner) {
+		r.certCheckInterval = interval
+	}
+}
+
+// NewHTTPServerRunner creates new Runner serving the http.Server.
+// The server is shut down gracefully when the context is canceled.
+// The Runner reports ready (see Ready) when the listener is bound.
+func NewHTTPServerRunner(server *http.Server, opts ...HTTPServerOption) Runner {
+	r := &httpServerRunner{
+		server:            server,
+		drainTimeout:      DefaultHTTPDrainTimeout,
+		certCheckInterval: DefaultHTTPCertCheckInterval,
+	}
+
+	for _, fn := range opts {
+		fn(r)
+	}
+
+	if r.certs != nil {
+		r.certs.interval = r.certCheckInterval
+	}
+
+	return r
+}
+
+type httpServerRunner struct {
+	server            *http.Server
+	listener          net.Listener
+	drainTimeout      time.Duration
+	certs             *certReloader
+	certCheckInterval time.Duration
+}
+
+func (r *httpServerRunner) Run(ctx context.Context, _ *di.Container) error {
+	log := LoggerFromContext(ctx)
+
+	listener, err := r.listen(log)
+	if err != nil {
+		return err
+	}
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		if r.certs != nil {
+			serveErr <- r.server.ServeTLS(listener, "", "")
+
+			return
+		}
+
+		serveErr <- r.server.Serve(listener)
+	}()
+
+	log.Info("Serving HTTP on " + listener.Addr().String())
+	Ready(ctx)
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+
+		return fmt.Errorf("serve http: %w", err)
+	case <-ctx.Done():
+	}
+
+	if err := r.shutdown(log); err != nil {
+		return err
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve http: %w", err)
+	}
+
+	return nil
+}
+
+// Reload reloads the TLS certificate files, if TLS is enabled.
+func (r *httpServerRunner) Reload(_ context.Context) error {
+	if r.certs == nil {
+		return nil
+	}
+
+	return r.certs.load()
+}
+
+// listen returns the listener to serve on, prepares the TLS config if enabled.
+func (r *httpServerRunner) listen(log *zap.Logger) (net.Listener, error) {
+	if r.certs != nil {
+		r.certs.log = log
+
+		if err := r.certs.load(); err != nil {
+			return nil, err
+		}
+
+		cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+		if r.server.TLSConfig != nil {
+			cfg = r.server.TLSConfig.Clone()
+		}
+
+		cfg.GetCertificate = r.certs.getCertificate
+		r.server.TLSConfig = cfg
+	}
+
+	if r.listener != nil {
+		return r.listener, nil
+	}
+
+	addr := r.server.Addr
+	if addr == "" {
+		addr = ":http"
+
+		if r.certs != nil {
+			addr = ":https"
+		}
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen %s: %w", addr, err)
+	}
+
+	return listener, nil
+}
+
+// shutdown shuts the server down gracefully, closes it forcibly if the drain timeout exceeded.
+func (r *httpServerRunner) shutdown(log *zap.Logger) error {
+	ctx := context.Background()
+
+	if r.drainTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, r.drainTimeout)
+		defer cancel()
+	}
+
+	err := r.server.Shutdown(ctx)
+	if err == nil {
+		return nil
+	}
+
+	log.Warn("HTTP server graceful shutdown failed, closing", zap.Error(err))
+
+	if err := r.server.Close(); err != nil {
+		return fmt.Errorf("close http server: %w", err)
+	}
+
+	return nil
+}
+
+// certReloader is a TLS certificate loaded from the files and reloaded when they're changed.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+	log      *zap.Logger
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+// load loads the certificate files.
+func (c *certReloader) load() error {
+	modTime, err := c.filesModTime()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("load tls certificate: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cert = &cert
+	c.modTime = modTime
+	c.checked = time.Now()
+
+	return nil
+}
+
+// getCertificate returns the certificate, reloading it if the files are changed.
+func (c *certReloader) getCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+
+	if time.Since(c.checked) < c.interval {
+		defer c.mu.Unlock()
+
+		return c.cert, nil
+	}
+
+	c.checked = time.Now()
+	loaded := c.modTime
+
+	c.mu.Unlock()
+
+	if modTime, err := c.filesModTime(); err == nil && !modTime.Equal(loaded) {
+		if err := c.load(); err != nil && c.log != nil {
+			c.log.Warn("TLS certificate reload failed, using the previous one", zap.Error(err))
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cert, nil
+}
+
+// filesModTime returns the latest modification time of the certificate files.
+func (c *certReloader) filesModTime() (time.Time, error) {
+	var latest time.Time
+
+	for _, path := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("stat tls certificate file: %w", err)
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,186 @@
+package service_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runHTTPRunner(t *testing.T, runner service.Runner) (cancel func() error) {
+	t.Helper()
+
+	ctx, stop := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+
+	go func() {
+		result <- runner.Run(ctx, nil)
+	}()
+
+	return func() error {
+		stop()
+
+		select {
+		case err := <-result:
+			return err
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "runner is not stopped")
+
+			return nil
+		}
+	}
+}
+
+func TestHTTPServerRunner_WhenListener_ExpectServedAndShutdown(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}),
+		ReadHeaderTimeout: time.Second,
+	}
+
+	stop := runHTTPRunner(t, service.NewHTTPServerRunner(server, service.WithHTTPListener(listener)))
+
+	resp, err := http.Get("http://" + listener.Addr().String())
+	require.NoError(t, err)
+
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, "ok", string(body))
+	assert.NoError(t, stop())
+
+	_, err = http.Get("http://" + listener.Addr().String())
+	assert.Error(t, err)
+}
+
+func TestHTTPServerRunner_WhenDrainTimeout_ExpectClosed(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	handling := make(chan struct{})
+	stuck := make(chan struct{})
+
+	defer close(stuck)
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			close(handling)
+			<-stuck
+		}),
+		ReadHeaderTimeout: time.Second,
+	}
+
+	stop := runHTTPRunner(t, service.NewHTTPServerRunner(
+		server,
+		service.WithHTTPListener(listener),
+		service.WithHTTPDrainTimeout(50*time.Millisecond),
+	))
+
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	<-handling
+
+	start := time.Now()
+
+	assert.NoError(t, stop())
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestHTTPServerRunner_WhenTLSFilesChanged_ExpectReloaded(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	writeTestCert(t, certFile, keyFile, 1)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	runner := service.NewHTTPServerRunner(
+		&http.Server{Handler: http.NotFoundHandler(), ReadHeaderTimeout: time.Second},
+		service.WithHTTPListener(listener),
+		service.WithHTTPTLS(certFile, keyFile),
+		service.WithHTTPCertCheckInterval(0),
+	)
+
+	stop := runHTTPRunner(t, runner)
+
+	require.Eventually(t, func() bool {
+		return servedCertSerial(listener.Addr().String()) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	writeTestCert(t, certFile, keyFile, 2)
+
+	now := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(certFile, now, now))
+
+	assert.Equal(t, int64(2), servedCertSerial(listener.Addr().String()))
+
+	writeTestCert(t, certFile, keyFile, 3)
+	require.NoError(t, runner.(service.Reloadable).Reload(context.Background()))
+
+	assert.Equal(t, int64(3), servedCertSerial(listener.Addr().String()))
+	assert.NoError(t, stop())
+}
+
+func servedCertSerial(addr string) int64 {
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+	if err != nil {
+		return 0
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+}
+
+func writeTestCert(t *testing.T, certFile string, keyFile string, serial int64) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+}
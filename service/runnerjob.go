@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/logtools"
+	"go.uber.org/zap"
+)
+
+// ErrNoNextRun is returned by the scheduled Runner when its schedule has no next activation time
+// (or the periodic Runner's interval is not positive).
+var ErrNoNextRun = errors.New("schedule has no next run")
+
+// OverlapPolicy defines what happens when the job run is due while the previous one is still running.
+type OverlapPolicy int
+
+// Overlap policies
+const (
+	// OverlapSkip skips the due run.
+	OverlapSkip OverlapPolicy = iota
+
+	// OverlapQueue starts the due run after the previous one is finished.
+	// At most one run is queued, others are skipped.
+	OverlapQueue
+
+	// OverlapAllow starts the due run concurrently with the previous one.
+	OverlapAllow
+)
+
+// JobOption is an option of the periodic and cron job runners.
+type JobOption func(r *jobRunner)
+
+// WithJobJitter sets the maximum random delay added to each run time.
+func WithJobJitter(jitter time.Duration) JobOption {
+	return func(r *jobRunner) {
+		r.jitter = jitter
+	}
+}
+
+// WithJobOverlap sets the overlap policy, default is OverlapSkip.
+func WithJobOverlap(policy OverlapPolicy) JobOption {
+	return func(r *jobRunner) {
+		r.overlap = policy
+	}
+}
+
+// WithJobTimeout sets the timeout of the single job run.
+// Zero timeout (default) means no timeout.
+func WithJobTimeout(timeout time.Duration) JobOption {
+	return func(r *jobRunner) {
+		r.timeout = timeout
+	}
+}
+
+// WithJobLocation sets the time zone the schedule is calculated in,
+// default is the local one. The CRON_TZ spec prefix takes precedence, see ParseCron.
+func WithJobLocation(loc *time.Location) JobOption {
+	return func(r *jobRunner) {
+		r.loc = loc
+	}
+}
+
+// NewPeriodicRunner creates new Runner executing the function with the given interval.
+// The first run is executed after the interval passed.
+// Run errors are logged, panics are recovered and logged, the Runner keeps running until the context is canceled.
+func NewPeriodicRunner(interval time.Duration, fn RunnerRunFn, opts ...JobOption) Runner {
+	return NewScheduleRunner(periodicSchedule(interval), fn, opts...)
+}
+
+// NewCronRunner creates new Runner executing the function by the cron spec, see ParseCron.
+// Run errors are logged, panics are recovered and logged, the Runner keeps running until the context is canceled.
+func NewCronRunner(spec string, fn RunnerRunFn, opts ...JobOption) (Runner, error) {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewScheduleRunner(schedule, fn, opts...), nil
+}
+
+// NewScheduleRunner creates new Runner executing the function by the schedule.
+func NewScheduleRunner(schedule Schedule, fn RunnerRunFn, opts ...JobOption) Runner {
+	r := &jobRunner{
+		schedule: schedule,
+		fn:       fn,
+		overlap:  OverlapSkip,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// periodicSchedule is a Schedule with the fixed interval
+type periodicSchedule time.Duration
+
+func (s periodicSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+type jobRunner struct {
+	schedule Schedule
+	fn       RunnerRunFn
+	jitter   time.Duration
+	overlap  OverlapPolicy
+	timeout  time.Duration
+	loc      *time.Location
+}
+
+func (r *jobRunner) Run(ctx context.Context, ctn *di.Container) error {
+	log := LoggerFromContext(ctx)
+	wg := sync.WaitGroup{}
+
+	defer wg.Wait()
+
+	trigger := r.trigger(ctx, ctn, log, &wg)
+
+	Ready(ctx)
+
+	for {
+		now := time.Now()
+		if r.loc != nil {
+			now = now.In(r.loc)
+		}
+
+		next := r.schedule.Next(now)
+		if next.IsZero() || !next.After(now) {
+			return ErrNoNextRun
+		}
+
+		delay := next.Sub(now)
+		if r.jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(r.jitter))) //nolint:gosec
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil
+		case <-timer.C:
+		}
+
+		trigger()
+	}
+}
+
+// trigger returns the function starting the job run according to the overlap policy.
+func (r *jobRunner) trigger(ctx context.Context, ctn *di.Container, log *zap.Logger, wg *sync.WaitGroup) func() {
+	switch r.overlap {
+	case OverlapAllow:
+		return func() {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				r.runOnce(ctx, ctn, log)
+			}()
+		}
+
+	case OverlapQueue:
+		pending := make(chan struct{}, 1)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-pending:
+					r.runOnce(ctx, ctn, log)
+				}
+			}
+		}()
+
+		return func() {
+			select {
+			case pending <- struct{}{}:
+			default:
+				log.Warn("Job run is skipped: previous run is still running and one is queued")
+			}
+		}
+
+	default:
+		busy := atomic.Bool{}
+
+		return func() {
+			if !busy.CompareAndSwap(false, true) {
+				log.Warn("Job run is skipped: previous run is still running")
+
+				return
+			}
+
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				defer busy.Store(false)
+
+				r.runOnce(ctx, ctn, log)
+			}()
+		}
+	}
+}
+
+// runOnce executes the job function with the timeout, logs its error or panic.
+func (r *jobRunner) runOnce(ctx context.Context, ctn *di.Container, log *zap.Logger) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	defer logtools.CatchPanic(log)
+
+	if err := r.fn(ctx, ctn); err != nil {
+		log.Error(fmt.Sprintf("Job run failed: %s", err.Error()), zap.Duration("duration", time.Since(start)))
+
+		return
+	}
+
+	log.Debug("Job run is done", zap.Duration("duration", time.Since(start)))
+}
@@ -0,0 +1,113 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runJobRunner(t *testing.T, runner service.Runner, duration time.Duration) error {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	return runner.Run(ctx, &di.Container{})
+}
+
+func TestPeriodicRunner_WhenFailingAndPanicking_ExpectKeptRunning(t *testing.T) {
+	runs := atomic.Int32{}
+	ctn := &di.Container{}
+
+	runner := service.NewPeriodicRunner(10*time.Millisecond, func(_ context.Context, c *di.Container) error {
+		assert.Same(t, ctn, c)
+
+		if runs.Add(1)%2 == 0 {
+			panic("test panic")
+		}
+
+		return errors.New("job error")
+	}, service.WithJobJitter(time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := runner.Run(ctx, ctn)
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, runs.Load(), int32(4))
+}
+
+func TestPeriodicRunner_WhenOverlap_ExpectPolicyApplied(t *testing.T) {
+	tests := []struct {
+		Policy     service.OverlapPolicy
+		Concurrent bool
+	}{
+		{service.OverlapSkip, false},
+		{service.OverlapQueue, false},
+		{service.OverlapAllow, true},
+	}
+
+	for _, test := range tests {
+		runs := atomic.Int32{}
+		running := atomic.Int32{}
+		maxRunning := atomic.Int32{}
+
+		runner := service.NewPeriodicRunner(5*time.Millisecond, func(ctx context.Context, _ *di.Container) error {
+			runs.Add(1)
+
+			current := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				prev := maxRunning.Load()
+				if current <= prev || maxRunning.CompareAndSwap(prev, current) {
+					break
+				}
+			}
+
+			<-ctx.Done()
+
+			return nil
+		}, service.WithJobOverlap(test.Policy), service.WithJobTimeout(40*time.Millisecond))
+
+		err := runJobRunner(t, runner, 100*time.Millisecond)
+
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, runs.Load(), int32(2), "policy %d", test.Policy)
+		assert.Equal(t, test.Concurrent, maxRunning.Load() > 1, "policy %d", test.Policy)
+		assert.Equal(t, int32(0), running.Load(), "policy %d", test.Policy)
+	}
+}
+
+func TestPeriodicRunner_WhenNoInterval_ExpectError(t *testing.T) {
+	runner := service.NewPeriodicRunner(0, func(_ context.Context, _ *di.Container) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, runJobRunner(t, runner, time.Second), service.ErrNoNextRun)
+}
+
+func TestCronRunner_WhenEverySecond_ExpectRun(t *testing.T) {
+	runs := atomic.Int32{}
+
+	runner, err := service.NewCronRunner("* * * * * *", func(_ context.Context, _ *di.Container) error {
+		runs.Add(1)
+
+		return nil
+	}, service.WithJobLocation(time.UTC))
+	require.NoError(t, err)
+
+	require.NoError(t, runJobRunner(t, runner, 1100*time.Millisecond))
+	assert.GreaterOrEqual(t, runs.Load(), int32(1))
+
+	_, err = service.NewCronRunner("invalid", nil)
+	assert.ErrorIs(t, err, service.ErrInvalidCronSpec)
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/logtools"
+	"go.uber.org/zap"
+)
+
+// ErrJobsUnfinished is returned by the worker pool Runner
+// when the in-flight jobs are not finished within the drain timeout.
+var ErrJobsUnfinished = errors.New("jobs are not finished")
+
+// Default worker pool runner options.
+const (
+	DefaultWorkerPoolDrainTimeout = 10 * time.Second
+	DefaultWorkerPoolFetchBackoff = time.Second
+	DefaultWorkerPoolStopTimeout  = time.Second
+)
+
+// JobHandlerFn is a function processing the job fetched by the worker pool Runner.
+type JobHandlerFn func(ctx context.Context, ctn *di.Container, job Job) error
+
+// DeadLetterFn is a function receiving the job failed after all retries with the last error.
+type DeadLetterFn func(ctx context.Context, job Job, err error)
+
+// WorkerPoolOption is an option of the worker pool Runner.
+type WorkerPoolOption func(p *workerPool)
+
+// WithPoolRetries sets the maximum retries count of the failed job and the delay between retries.
+// Default is no retries, negative retries count is treated as zero.
+func WithPoolRetries(retries int, backoff time.Duration) WorkerPoolOption {
+	if retries < 0 {
+		retries = 0
+	}
+
+	return func(p *workerPool) {
+		p.retries = retries
+		p.backoff = backoff
+	}
+}
+
+// WithPoolDeadLetter sets the function receiving the jobs failed after all retries.
+// Dead-lettered jobs are acknowledged, without the dead-letter function they're rejected
+// with Nack without requeue.
+func WithPoolDeadLetter(fn DeadLetterFn) WorkerPoolOption {
+	return func(p *workerPool) {
+		p.deadLetter = fn
+	}
+}
+
+// WithPoolDrainTimeout sets the maximum duration to wait for the in-flight jobs on the shutdown.
+// When exceeded, the jobs context is canceled, the unfinished jobs are requeued with Nack
+// and the Runner returns the ErrJobsUnfinished error listing them.
+// After the jobs context is canceled, the workers are awaited for the DefaultWorkerPoolStopTimeout,
+// handlers ignoring the context and still running after it are abandoned (and logged).
+// Default is DefaultWorkerPoolDrainTimeout, zero timeout means waiting forever.
+func WithPoolDrainTimeout(timeout time.Duration) WorkerPoolOption {
+	return func(p *workerPool) {
+		p.drainTimeout = timeout
+	}
+}
+
+// WithPoolFetchBackoff sets the delay before the next fetch after the Source failure.
+// Default is DefaultWorkerPoolFetchBackoff.
+func WithPoolFetchBackoff(backoff time.Duration) WorkerPoolOption {
+	return func(p *workerPool) {
+		p.fetchBackoff = backoff
+	}
+}
+
+// NewWorkerPoolRunner creates new Runner processing the jobs from the Source with the given count of workers.
+// The job is acknowledged when the handler succeeded, retried when failed (see WithPoolRetries),
+// then passed to the dead-letter function (see WithPoolDeadLetter). Handler panics are treated as failures.
+//
+// When the context is canceled, the workers stop fetching and the in-flight jobs are finished
+// within the drain timeout (see WithPoolDrainTimeout). When the Source is closed, the Runner returns.
+func NewWorkerPoolRunner(source Source, workers int, handler JobHandlerFn, opts ...WorkerPoolOption) Runner {
+	p := &workerPool{
+		source:       source,
+		workers:      workers,
+		handler:      handler,
+		drainTimeout: DefaultWorkerPoolDrainTimeout,
+		fetchBackoff: DefaultWorkerPoolFetchBackoff,
+	}
+
+	if p.workers < 1 {
+		p.workers = 1
+	}
+
+	for _, fn := range opts {
+		fn(p)
+	}
+
+	return p
+}
+
+type workerPool struct {
+	source       Source
+	workers      int
+	handler      JobHandlerFn
+	retries      int
+	backoff      time.Duration
+	deadLetter   DeadLetterFn
+	drainTimeout time.Duration
+	fetchBackoff time.Duration
+}
+
+// inflightJob is a job being processed, settled (acknowledged or rejected) once
+type inflightJob struct {
+	job     Job
+	settled atomic.Bool
+}
+
+// poolRun is a single worker pool run state
+type poolRun struct {
+	*workerPool
+
+	ctn      *di.Container
+	log      *zap.Logger
+	jobCtx   context.Context
+	mu       sync.Mutex
+	inflight map[*inflightJob]struct{}
+}
+
+func (p *workerPool) Run(ctx context.Context, ctn *di.Container) error {
+	jobCtx, cancelJobs := context.WithCancel(detachedContext{parent: ctx})
+	defer cancelJobs()
+
+	run := &poolRun{
+		workerPool: p,
+		ctn:        ctn,
+		log:        LoggerFromContext(ctx),
+		jobCtx:     jobCtx,
+		inflight:   make(map[*inflightJob]struct{}),
+	}
+
+	done := run.start(ctx)
+
+	Ready(ctx)
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	return run.drain(done, cancelJobs)
+}
+
+// start starts the workers, returns the channel closed when all of them are returned.
+func (r *poolRun) start(ctx context.Context) <-chan struct{} {
+	wg := sync.WaitGroup{}
+	done := make(chan struct{})
+
+	wg.Add(r.workers)
+
+	for i := 0; i < r.workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			r.work(ctx)
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	return done
+}
+
+// drain waits for the in-flight jobs within the drain timeout,
+// then cancels the jobs context and requeues the unfinished jobs.
+func (r *poolRun) drain(done <-chan struct{}, cancelJobs context.CancelFunc) error {
+	var deadline <-chan time.Time
+
+	if r.drainTimeout > 0 {
+		timer := time.NewTimer(r.drainTimeout)
+		defer timer.Stop()
+
+		deadline = timer.C
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-deadline:
+	}
+
+	cancelJobs()
+
+	stopTimer := time.NewTimer(DefaultWorkerPoolStopTimeout)
+	defer stopTimer.Stop()
+
+	select {
+	case <-done:
+	case <-stopTimer.C:
+		r.log.Warn("Worker pool workers are not stopped after the jobs cancellation, abandoning them")
+	}
+
+	if ids := r.abandon(); len(ids) > 0 {
+		r.log.Error("Worker pool drain timeout exceeded, jobs are requeued", zap.Strings("unfinished_jobs", ids))
+
+		return fmt.Errorf("%w: %s", ErrJobsUnfinished, strings.Join(ids, ", "))
+	}
+
+	return nil
+}
+
+// work fetches and processes the jobs until the context is canceled or the Source is closed.
+func (r *poolRun) work(ctx context.Context) {
+	for {
+		job, err := r.source.Fetch(ctx)
+
+		switch {
+		case ctx.Err() != nil, errors.Is(err, ErrSourceClosed):
+			if job != nil {
+				_ = job.Nack(context.Background(), true)
+			}
+
+			return
+		case err != nil:
+			r.log.Error("Worker pool failed to fetch the job", zap.Error(err))
+
+			if !sleepContext(ctx, r.fetchBackoff) {
+				return
+			}
+
+			continue
+		}
+
+		r.process(job)
+	}
+}
+
+// process handles the job with retries, settles it with the result.
+// The job left unsettled when the jobs context is canceled stays in-flight to be requeued by abandon.
+func (r *poolRun) process(job Job) {
+	item := &inflightJob{job: job}
+	log := r.log.With(zap.String("job_id", job.ID()))
+
+	r.track(item, true)
+
+	defer func() {
+		if item.settled.Load() {
+			r.track(item, false)
+		}
+	}()
+
+	var err error
+
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		if attempt > 0 && !sleepContext(r.jobCtx, r.backoff) {
+			return
+		}
+
+		if err = r.handle(job, log); err == nil {
+			r.settle(item, log, func(ctx context.Context) error { return job.Ack(ctx) })
+
+			return
+		}
+
+		if r.jobCtx.Err() != nil {
+			return
+		}
+
+		log.Warn("Job failed", zap.Error(err), zap.Int("attempt", attempt+1))
+	}
+
+	if r.deadLetter == nil {
+		log.Error("Job failed after all retries, rejecting", zap.Error(err))
+		r.settle(item, log, func(ctx context.Context) error { return job.Nack(ctx, false) })
+
+		return
+	}
+
+	r.settle(item, log, func(ctx context.Context) error {
+		r.deadLetter(ctx, job, err)
+
+		return job.Ack(ctx)
+	})
+}
+
+// handle executes the handler, converts panic to an error.
+func (r *poolRun) handle(job Job, log *zap.Logger) (err error) {
+	defer logtools.CatchPanic(log, func(recovered any) {
+		err = fmt.Errorf("job handler panicked: %v", recovered)
+	})
+
+	return r.handler(r.jobCtx, r.ctn, job)
+}
+
+// settle acknowledges or rejects the job once.
+func (r *poolRun) settle(item *inflightJob, log *zap.Logger, fn func(ctx context.Context) error) {
+	if !item.settled.CompareAndSwap(false, true) {
+		return
+	}
+
+	if err := fn(context.Background()); err != nil {
+		log.Error("Failed to settle the job", zap.Error(err))
+	}
+}
+
+// track adds or removes the in-flight job.
+func (r *poolRun) track(item *inflightJob, add bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if add {
+		r.inflight[item] = struct{}{}
+	} else {
+		delete(r.inflight, item)
+	}
+}
+
+// abandon requeues the unsettled in-flight jobs, returns their IDs.
+func (r *poolRun) abandon() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make([]string, 0)
+
+	for item := range r.inflight {
+		if !item.settled.CompareAndSwap(false, true) {
+			continue
+		}
+
+		if err := item.job.Nack(context.Background(), true); err != nil {
+			r.log.Error("Failed to requeue the job", zap.String("job_id", item.job.ID()), zap.Error(err))
+		}
+
+		ids = append(ids, item.job.ID())
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// sleepContext sleeps for the duration, returns false if the context is canceled before.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
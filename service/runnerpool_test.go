@@ -0,0 +1,276 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSource records the settled jobs of the ChanSource
+type recordingSource struct {
+	*service.ChanSource
+
+	mu     sync.Mutex
+	acked  []string
+	nacked []string
+}
+
+func (s *recordingSource) Fetch(ctx context.Context) (service.Job, error) {
+	job, err := s.ChanSource.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingJob{Job: job, source: s}, nil
+}
+
+func (s *recordingSource) settled() (acked []string, nacked []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.acked...), append([]string(nil), s.nacked...)
+}
+
+type recordingJob struct {
+	service.Job
+
+	source *recordingSource
+}
+
+func (j *recordingJob) Ack(ctx context.Context) error {
+	j.source.mu.Lock()
+	j.source.acked = append(j.source.acked, j.ID())
+	j.source.mu.Unlock()
+
+	return j.Job.Ack(ctx)
+}
+
+func (j *recordingJob) Nack(ctx context.Context, requeue bool) error {
+	j.source.mu.Lock()
+	j.source.nacked = append(j.source.nacked, j.ID())
+	j.source.mu.Unlock()
+
+	return j.Job.Nack(ctx, requeue)
+}
+
+func pushJobs(t *testing.T, source *service.ChanSource, payloads ...any) {
+	t.Helper()
+
+	for _, payload := range payloads {
+		_, err := source.Push(context.Background(), payload)
+		require.NoError(t, err)
+	}
+}
+
+func TestWorkerPoolRunner_WhenSourceClosed_ExpectAllProcessed(t *testing.T) {
+	source := &recordingSource{ChanSource: service.NewChanSource(10)}
+	sum := atomic.Int64{}
+
+	pushJobs(t, source.ChanSource, 1, 2, 3, 4, 5)
+	source.Close()
+
+	runner := service.NewWorkerPoolRunner(source, 3, func(_ context.Context, _ *di.Container, job service.Job) error {
+		sum.Add(int64(job.Payload().(int)))
+
+		return nil
+	})
+
+	err := runner.Run(context.Background(), &di.Container{})
+
+	acked, nacked := source.settled()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15), sum.Load())
+	assert.ElementsMatch(t, []string{"1", "2", "3", "4", "5"}, acked)
+	assert.Empty(t, nacked)
+}
+
+func TestWorkerPoolRunner_WhenFailing_ExpectRetriedAndDeadLettered(t *testing.T) {
+	source := &recordingSource{ChanSource: service.NewChanSource(10)}
+	attempts := atomic.Int32{}
+	dead := make([]string, 0)
+
+	pushJobs(t, source.ChanSource, "fails")
+	source.Close()
+
+	runner := service.NewWorkerPoolRunner(
+		source,
+		1,
+		func(_ context.Context, _ *di.Container, _ service.Job) error {
+			if attempts.Add(1) == 2 {
+				panic("test panic")
+			}
+
+			return errors.New("job error")
+		},
+		service.WithPoolRetries(2, time.Millisecond),
+		service.WithPoolDeadLetter(func(_ context.Context, job service.Job, err error) {
+			assert.EqualError(t, err, "job error")
+
+			dead = append(dead, job.ID())
+		}),
+	)
+
+	require.NoError(t, runner.Run(context.Background(), &di.Container{}))
+
+	acked, nacked := source.settled()
+
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, []string{"1"}, dead)
+	assert.Equal(t, []string{"1"}, acked)
+	assert.Empty(t, nacked)
+}
+
+func TestWorkerPoolRunner_WhenFailingWithoutDeadLetter_ExpectRejected(t *testing.T) {
+	source := &recordingSource{ChanSource: service.NewChanSource(10)}
+
+	pushJobs(t, source.ChanSource, "fails")
+	source.Close()
+
+	runner := service.NewWorkerPoolRunner(source, 1, func(_ context.Context, _ *di.Container, _ service.Job) error {
+		return errors.New("job error")
+	})
+
+	require.NoError(t, runner.Run(context.Background(), &di.Container{}))
+
+	acked, nacked := source.settled()
+
+	assert.Empty(t, acked)
+	assert.Equal(t, []string{"1"}, nacked)
+	assert.Equal(t, 0, source.Len())
+}
+
+func TestWorkerPoolRunner_WhenNegativeRetries_ExpectHandledOnce(t *testing.T) {
+	source := &recordingSource{ChanSource: service.NewChanSource(10)}
+	attempts := atomic.Int32{}
+
+	pushJobs(t, source.ChanSource, "fails")
+	source.Close()
+
+	runner := service.NewWorkerPoolRunner(
+		source,
+		1,
+		func(_ context.Context, _ *di.Container, _ service.Job) error {
+			attempts.Add(1)
+
+			return errors.New("job error")
+		},
+		service.WithPoolRetries(-1, time.Millisecond),
+	)
+
+	require.NoError(t, runner.Run(context.Background(), &di.Container{}))
+
+	acked, nacked := source.settled()
+
+	assert.Equal(t, int32(1), attempts.Load())
+	assert.Empty(t, acked)
+	assert.Equal(t, []string{"1"}, nacked)
+}
+
+func TestWorkerPoolRunner_WhenShutdown_ExpectInFlightFinished(t *testing.T) {
+	source := &recordingSource{ChanSource: service.NewChanSource(10)}
+	started := make(chan struct{})
+
+	pushJobs(t, source.ChanSource, "slow")
+
+	runner := service.NewWorkerPoolRunner(source, 1, func(_ context.Context, _ *di.Container, _ service.Job) error {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+
+		return nil
+	}, service.WithPoolDrainTimeout(time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	require.NoError(t, runner.Run(ctx, &di.Container{}))
+
+	acked, _ := source.settled()
+
+	assert.Equal(t, []string{"1"}, acked)
+}
+
+func TestWorkerPoolRunner_WhenDrainTimeout_ExpectUnfinishedReported(t *testing.T) {
+	source := &recordingSource{ChanSource: service.NewChanSource(10)}
+	started := make(chan struct{})
+	stuck := make(chan struct{})
+
+	defer close(stuck)
+
+	pushJobs(t, source.ChanSource, "stuck")
+
+	runner := service.NewWorkerPoolRunner(source, 2, func(ctx context.Context, _ *di.Container, _ service.Job) error {
+		close(started)
+
+		select {
+		case <-stuck:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, service.WithPoolDrainTimeout(20*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	err := runner.Run(ctx, &di.Container{})
+
+	assert.ErrorIs(t, err, service.ErrJobsUnfinished)
+	assert.ErrorContains(t, err, "1")
+
+	acked, nacked := source.settled()
+
+	assert.Empty(t, acked)
+	assert.Equal(t, []string{"1"}, nacked)
+	assert.Equal(t, 1, source.Len())
+}
+
+func TestWorkerPoolRunner_WhenCanceledDuringBackoff_ExpectUnfinishedReported(t *testing.T) {
+	source := &recordingSource{ChanSource: service.NewChanSource(10)}
+	failed := make(chan struct{})
+	attempts := atomic.Int32{}
+
+	pushJobs(t, source.ChanSource, "retried")
+
+	runner := service.NewWorkerPoolRunner(source, 1, func(_ context.Context, _ *di.Container, _ service.Job) error {
+		if attempts.Add(1) == 1 {
+			close(failed)
+		}
+
+		return errors.New("job error")
+	}, service.WithPoolRetries(1, time.Minute), service.WithPoolDrainTimeout(20*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-failed
+		cancel()
+	}()
+
+	err := runner.Run(ctx, &di.Container{})
+
+	assert.ErrorIs(t, err, service.ErrJobsUnfinished)
+	assert.Equal(t, int32(1), attempts.Load())
+
+	acked, nacked := source.settled()
+
+	assert.Empty(t, acked)
+	assert.Equal(t, []string{"1"}, nacked)
+	assert.Equal(t, 1, source.Len())
+}
@@ -14,8 +14,12 @@ type Router interface {
 }
 
 // NewRouterRunner creates new Runner executing the given http Router.
+// The Runner reports ready (see Ready) when the Router is started,
+// the Router interface doesn't tell when it's listening.
 func NewRouterRunner(router Router) Runner {
 	return NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+		Ready(ctx)
+
 		if err := router.RunWithContext(ctx); err != nil {
 			return fmt.Errorf("run router: %w", err)
 		}
@@ -2,26 +2,20 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"sync/atomic"
-	"syscall"
+	"time"
 
 	"github.com/kukymbr/core2go/di"
 	"github.com/kukymbr/core2go/logtools"
 	"go.uber.org/zap"
-	"golang.org/x/sync/errgroup"
-)
-
-var (
-	errTerminated = errors.New("terminated")
-	errCanceled   = errors.New("canceled")
 )
 
 // New creates new Service instance with the specified DI container.
-func New(ctn *di.Container, log *zap.Logger) *Service {
+func New(ctn *di.Container, log *zap.Logger, opts ...Option) *Service {
 	if log == nil {
 		log = zap.Must(zap.NewProduction())
 	}
@@ -29,7 +23,11 @@ func New(ctn *di.Container, log *zap.Logger) *Service {
 	return &Service{
 		ctn:     ctn,
 		log:     log.With(zap.String("who", "core2go.Service")),
+		opt:     newOptions(opts),
 		runners: make([]Runner, 0),
+		states:  make([]*runnerState, 0),
+		stopped: make(chan struct{}),
+		done:    make(chan struct{}),
 	}
 }
 
@@ -37,126 +35,517 @@ func New(ctn *di.Container, log *zap.Logger) *Service {
 type Service struct {
 	ctn     *di.Container
 	log     *zap.Logger
+	opt     *options
 	runners []Runner
+	states  []*runnerState
+
+	// registry guards the runners registration,
+	// runners and states are not changed after the Service is executed
+	registry sync.RWMutex
 
 	executed atomic.Bool
+	running  atomic.Bool
+	exitCode atomic.Int32
+
+	trigger atomic.Int32
+	signal  os.Signal
+
+	failureMu   sync.Mutex
+	failureCode failureCodeKind
+
+	stopOnce   sync.Once
+	stopped    chan struct{}
+	stopReason string
+
+	doneOnce sync.Once
+	done     chan struct{}
+
+	reloading      atomic.Bool
+	reloads        atomic.Int32
+	reloadFailures atomic.Int32
+
+	// reloadsWG awaits the signaled reloads before the container is closed
+	reloadsWG sync.WaitGroup
 }
 
 // RegisterRunner register the Runner instances in the Service.
 func (s *Service) RegisterRunner(runners ...Runner) {
+	s.registry.Lock()
+	defer s.registry.Unlock()
+
 	if s.executed.Load() {
 		s.log.Panic("service is already executed, cannot register the runner")
 	}
 
-	s.runners = append(s.runners, runners...)
+	for _, runner := range runners {
+		s.states = append(s.states, &runnerState{
+			name:  nameOf(runner, len(s.runners)),
+			role:  roleOf(runner),
+			stage: stageOf(runner),
+		})
+		s.runners = append(s.runners, runner)
+	}
 }
 
 // Run starts the Service. Returns the exist code.
-//
-//nolint:funlen
 func (s *Service) Run(ctx context.Context) int {
+	return s.RunWithResult(ctx).ExitCode
+}
+
+// RunWithResult starts the Service. Returns the Service execution outcome:
+// the stop trigger, the exit code and the outcomes of all runners.
+func (s *Service) RunWithResult(ctx context.Context) RunResult {
 	var cancel context.CancelFunc
 
+	s.registry.Lock()
 	s.executed.Store(true)
+	s.registry.Unlock()
 
-	ctx, cancel = context.WithCancel(ctx)
-	defer func() {
-		cancel()
-	}()
+	s.running.Store(true)
 
-	defer s.close()
+	defer s.doneOnce.Do(func() { close(s.done) })
+	defer s.running.Store(false)
+
+	ctx, cancel = context.WithCancel(ctx)
+	defer cancel()
 
 	if len(s.runners) == 0 {
 		s.log.Error("no runners registered in the Service instance")
+		s.exitCode.Store(int32(s.opt.exitCodes.NoRunners))
+		s.setTrigger(TriggerNoRunners)
+		s.close()
 
-		return 1
+		return s.result(false)
 	}
 
-	exitCode := atomic.Int32{}
-	runnersDone := atomic.Int32{}
+	signals, stopSignals := s.notifySignals()
+	defer stopSignals()
 
-	shutdownChan := make(chan os.Signal, 1)
-	signal.Notify(shutdownChan, syscall.SIGINT, syscall.SIGHUP, syscall.SIGTERM)
+	done := s.startRunners(ctx, cancel)
 
-	eg := errgroup.Group{}
+	forced, abandoned := s.wait(ctx, cancel, signals, done)
+	if !forced && !abandoned {
+		s.reloadsWG.Wait()
+		s.close()
+	}
 
-	eg.Go(func() error {
-		return s.listenCancel(ctx, cancel, &exitCode, shutdownChan)
+	s.log.Debug(fmt.Sprintf("Got exit code: %d", s.exitCode.Load()), s.runnersReportField())
+
+	return s.result(forced)
+}
+
+// Stop stops the running Service with the given reason as the OS stop signal does.
+// If called before the Run, the Service is stopped right after it's started.
+// Subsequent calls are ignored.
+func (s *Service) Stop(reason string) {
+	s.stopOnce.Do(func() {
+		s.stopReason = reason
+		close(s.stopped)
 	})
+}
+
+// Done returns the channel closed when the Service Run is finished.
+func (s *Service) Done() <-chan struct{} {
+	return s.done
+}
 
-	for _, runner := range s.runners {
-		runner := runner
+// startRunners starts the runners by stages, returns the channel closed when all runners are returned.
+func (s *Service) startRunners(ctx context.Context, cancel context.CancelFunc) <-chan struct{} {
+	wg := &sync.WaitGroup{}
+	done := make(chan struct{})
+	stages := s.stages(ctx)
 
-		eg.Go(func() error {
-			if err := ctx.Err(); err != nil {
-				exitCode.Store(2)
+	wg.Add(len(s.runners))
 
-				return err
-			}
+	for _, st := range stages {
+		st.wg.Add(len(st.runners))
+	}
 
-			if err := s.executeRunner(ctx, runner); err != nil {
-				exitCode.Store(3)
-				cancel()
+	go stopStages(ctx, stages)
+	go s.startStages(ctx, cancel, stages, wg, s.completion(cancel))
 
-				return err
-			}
+	go func() {
+		wg.Wait()
+
+		for _, st := range stages {
+			st.cancel()
+		}
+
+		close(done)
+	}()
+
+	return done
+}
+
+// completion returns the function counting the returned runners the Service waits for,
+// it completes the Service when all of them are returned.
+// Without critical runners, all runners are awaited, see RoleCritical.
+func (s *Service) completion(cancel context.CancelFunc) func(state *runnerState) {
+	awaited := s.countRole(RoleCritical)
+	awaitAll := awaited == 0
+	returned := atomic.Int32{}
+
+	if awaitAll {
+		awaited = len(s.runners)
+	}
+
+	return func(state *runnerState) {
+		if !awaitAll && state.role != RoleCritical {
+			return
+		}
 
-			done := runnersDone.Add(1)
-			if int(done) == len(s.runners) {
-				cancel()
+		if int(returned.Add(1)) == awaited && s.setTrigger(TriggerCompleted) {
+			cancel()
+		}
+	}
+}
+
+// startStages starts the runners of the stages, waiting for the stage to become ready before the next one.
+func (s *Service) startStages(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	stages []*stage,
+	wg *sync.WaitGroup,
+	completed func(state *runnerState),
+) {
+	for n, st := range stages {
+		for j, i := range st.runners {
+			st, i, ready := st, i, st.ready[j]
+			readyOnce := sync.Once{}
+			markReady := func() {
+				readyOnce.Do(func() { close(ready) })
 			}
 
-			return nil
-		})
+			go func() {
+				defer wg.Done()
+				defer st.wg.Done()
+				defer markReady()
+
+				s.runRunner(ctx, contextWithReady(st.ctx, markReady), cancel, i, completed)
+			}()
+		}
+
+		if n < len(stages)-1 {
+			s.waitReady(ctx, st)
+		}
 	}
+}
+
+// runRunner executes the runner with the stage context,
+// handles its failure or completion according to its role.
+func (s *Service) runRunner(
+	ctx context.Context,
+	stageCtx context.Context,
+	cancel context.CancelFunc,
+	i int,
+	completed func(state *runnerState),
+) {
+	state := s.states[i]
+
+	if err := ctx.Err(); err != nil {
+		if s.setTrigger(TriggerCanceled) || Trigger(s.trigger.Load()) == TriggerCanceled {
+			s.exitCode.CompareAndSwap(0, int32(s.opt.exitCodes.Canceled))
+		}
 
-	err := eg.Wait()
+		state.finish(RunnerCanceled, err, true)
 
-	cancel()
+		return
+	}
+
+	log := s.log.With(zap.String("runner", s.runnerName(i)))
 
-	if err != nil && !errors.Is(err, errTerminated) && !errors.Is(err, errCanceled) {
-		s.log.Error(err.Error())
+	state.start()
+
+	err := s.executeRunner(contextWithLogger(stageCtx, log), log, state.name, s.runners[i])
+	canceled := ctx.Err() != nil
+
+	switch {
+	case err != nil && state.role == RoleOptional:
+		log.Warn("Optional runner failed, ignoring: " + err.Error())
+		state.finish(RunnerFailed, err, canceled)
+		completed(state)
+
+	case err != nil:
+		log.Error(err.Error())
+		s.storeFailureExitCode(err)
+		s.setTrigger(TriggerRunnerFailed)
+		state.finish(RunnerFailed, err, canceled)
+		cancel()
+
+	case state.role == RoleDaemon:
+		state.finish(RunnerDone, nil, canceled)
+
+		if s.setTrigger(TriggerCompleted) {
+			log.Info("Daemon runner is done, stopping the Service")
+			cancel()
+		}
+
+	default:
+		state.finish(RunnerDone, nil, canceled)
+		completed(state)
+	}
+}
+
+// countRole returns count of the registered runners with the role.
+func (s *Service) countRole(role Role) int {
+	count := 0
+
+	for _, state := range s.states {
+		if state.role == role {
+			count++
+		}
 	}
 
-	s.log.Sugar().Debugf("Got exit code: %d", exitCode.Load())
+	return count
+}
+
+// waitState is the state of the Service waiting for the runners to return.
+type waitState struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// ctxDone and stopped are set to nil when handled
+	ctxDone <-chan struct{}
+	stopped <-chan struct{}
+
+	// deadline is the shutdown timeout channel, set on the shutdown
+	deadline <-chan time.Time
+	timer    *time.Timer
 
-	return int(exitCode.Load())
+	// signaled is true if the shutdown signal is received
+	signaled bool
 }
 
-func (s *Service) listenCancel(
+// wait waits for the runners to return, handling the OS signals and the shutdown timeout.
+// Returns forced=true if the exit is forced by the second signal,
+// abandoned=true if the runners are abandoned by the shutdown timeout.
+func (s *Service) wait(
 	ctx context.Context,
 	cancel context.CancelFunc,
-	exitCode *atomic.Int32,
-	shutdownChan chan os.Signal,
-) error {
+	signals <-chan os.Signal,
+	done <-chan struct{},
+) (forced bool, abandoned bool) {
+	w := &waitState{ctx: ctx, cancel: cancel, ctxDone: ctx.Done(), stopped: s.stopped}
+
+	defer func() {
+		if w.timer != nil {
+			w.timer.Stop()
+		}
+	}()
+
 	for {
 		select {
-		case sig := <-shutdownChan:
-			s.log.Info("Got shutdown signal: " + sig.String())
-			exitCode.Store(128)
+		case <-done:
+			return false, false
 
-			cancel()
+		case sig := <-signals:
+			if s.handleSignal(w, sig) {
+				return true, false
+			}
+
+		case <-w.stopped:
+			s.handleStop(w)
+
+		case <-w.ctxDone:
+			s.handleShutdown(w)
+
+		case <-w.deadline:
+			s.log.Error(
+				"Shutdown timeout exceeded, abandoning runners, the container is not closed",
+				s.stuckRunnersField(),
+			)
+
+			return false, true
+		}
+	}
+}
+
+// handleSignal forwards the signal, reloads or stops the Service on it.
+// Returns true if the exit is forced by the second shutdown signal.
+func (s *Service) handleSignal(w *waitState, sig os.Signal) (forced bool) {
+	if handler, ok := s.opt.forwardedSignals[sig]; ok {
+		s.log.Debug("Forwarding signal: " + sig.String())
+		handler(sig)
+
+		return false
+	}
+
+	if s.opt.isReloadSignal(sig) {
+		if w.ctxDone != nil {
+			s.reloadsWG.Add(1)
+
+			go s.reloadOnSignal(w.ctx, w.cancel)
+		}
+
+		return false
+	}
+
+	if w.signaled {
+		s.log.Warn("Got second shutdown signal, forcing exit: "+sig.String(), s.stuckRunnersField())
+		s.exitCode.Store(int32(s.opt.exitCodes.Forced))
+
+		return true
+	}
+
+	w.signaled = true
+
+	s.log.Info("Got shutdown signal: " + sig.String())
+
+	if s.setTrigger(TriggerSignal) {
+		s.exitCode.Store(int32(s.opt.signaledExitCode(sig)))
+		s.signal = sig
+	}
+
+	w.cancel()
+
+	return false
+}
+
+// handleStop stops the Service on the Stop call, if it's not stopped yet.
+func (s *Service) handleStop(w *waitState) {
+	w.stopped = nil
+
+	if w.ctxDone == nil || !s.setTrigger(TriggerStopped) {
+		return
+	}
+
+	s.log.Info("Stopping the Service: " + s.stopReason)
+	s.exitCode.Store(int32(s.opt.exitCodes.Stopped))
+
+	w.cancel()
+}
+
+// handleShutdown starts the shutdown timeout when the Service context is done.
+func (s *Service) handleShutdown(w *waitState) {
+	s.log.Debug("Finalizing the Service")
+	s.setTrigger(TriggerCanceled)
+
+	w.ctxDone = nil
+
+	if s.opt.shutdownTimeout > 0 {
+		w.timer = time.NewTimer(s.opt.shutdownTimeout)
+		w.deadline = w.timer.C
+	}
+}
 
-			return errTerminated
+// reloadOnSignal reloads the Service, stops it if reload failed and failures are fatal.
+func (s *Service) reloadOnSignal(ctx context.Context, cancel context.CancelFunc) {
+	defer s.reloadsWG.Done()
+
+	err := s.reload(ctx)
+	if err == nil || !s.opt.reloadFailureFatal {
+		return
+	}
+
+	s.log.Error("Reload failed, stopping the Service", zap.Error(err))
+	s.exitCode.Store(int32(s.opt.exitCodes.ReloadFailed))
+	s.setTrigger(TriggerReloadFailed)
+
+	cancel()
+}
+
+// stuckRunnersField returns the log field with names of the runners still running.
+func (s *Service) stuckRunnersField() zap.Field {
+	stuck := make([]string, 0)
+
+	for i, state := range s.states {
+		if status, _ := state.get(); status == RunnerRunning {
+			stuck = append(stuck, s.runnerName(i))
+		}
+	}
+
+	return zap.Strings("stuck_runners", stuck)
+}
+
+// runnersReportField returns the log field with the statuses of the runners by their names.
+func (s *Service) runnersReportField() zap.Field {
+	report := make([]string, 0, len(s.states))
+
+	for _, state := range s.states {
+		status, err := state.get()
+		item := state.name + ": " + status.String()
+
+		if err != nil {
+			item += " (" + err.Error() + ")"
+		}
+
+		report = append(report, item)
+	}
+
+	return zap.Strings("runners", report)
+}
+
+// registeredStates returns the snapshot of the registered runners states.
+func (s *Service) registeredStates() []*runnerState {
+	s.registry.RLock()
+	defer s.registry.RUnlock()
+
+	return append([]*runnerState(nil), s.states...)
+}
+
+// runnerName returns the name of the registered runner.
+func (s *Service) runnerName(i int) string {
+	return s.states[i].name
+}
+
+// nameOf returns the runner's name if it's a NamedRunner or wraps one,
+// or its index in the "#N" form otherwise.
+func nameOf(runner Runner, i int) string {
+	if named, ok := findRunner[NamedRunner](runner); ok && named.Name() != "" {
+		return named.Name()
+	}
+
+	return fmt.Sprintf("#%d", i)
+}
+
+// notifySignals subscribes to the OS signals, returns the signals channel and the unsubscribe function.
+// If the OS signals are disabled, the nil channel is returned,
+// if the signal source is set, it's returned as is.
+func (s *Service) notifySignals() (<-chan os.Signal, func()) {
+	if s.opt.signalsDisabled {
+		return nil, func() {}
+	}
+
+	if s.opt.signalSource != nil {
+		return s.opt.signalSource, func() {}
+	}
+
+	signals := make(chan os.Signal, 1)
+	subscribed := make([]os.Signal, 0, len(s.opt.stopSignals)+len(s.opt.reloadSignals)+len(s.opt.forwardedSignals))
+
+	subscribed = append(subscribed, s.opt.stopSignals...)
+	subscribed = append(subscribed, s.opt.reloadSignals...)
+	for sig := range s.opt.forwardedSignals {
+		subscribed = append(subscribed, sig)
+	}
+
+	if len(s.opt.ignoredSignals) > 0 {
+		signal.Ignore(s.opt.ignoredSignals...)
+	}
+
+	if len(subscribed) > 0 {
+		signal.Notify(signals, subscribed...)
+	}
 
-		case <-ctx.Done():
-			s.log.Debug("Finalizing the Service")
+	return signals, func() {
+		signal.Stop(signals)
 
-			return errCanceled
+		if len(s.opt.ignoredSignals) > 0 {
+			signal.Reset(s.opt.ignoredSignals...)
 		}
 	}
 }
 
-func (s *Service) executeRunner(ctx context.Context, runner Runner) error {
-	var panicRecovered any
+func (s *Service) executeRunner(ctx context.Context, log *zap.Logger, name string, runner Runner) error {
+	var panicked *panicError
 
 	err := func() error {
-		defer logtools.CatchPanic(s.log, func(recovered any) {
-			panicRecovered = recovered
+		defer logtools.CatchPanic(log, func(recovered any) {
+			panicked = newPanicError(name, recovered)
 		})
 
 		if err := runner.Run(ctx, s.ctn); err != nil {
-			return fmt.Errorf("failed to execute runner: %w", err)
+			return fmt.Errorf("failed to execute runner %s: %w", name, err)
 		}
 
 		return nil
@@ -165,8 +554,8 @@ func (s *Service) executeRunner(ctx context.Context, runner Runner) error {
 		return err
 	}
 
-	if panicRecovered != nil {
-		return fmt.Errorf("runner panicked: %v", panicRecovered)
+	if panicked != nil {
+		return panicked
 	}
 
 	return nil
@@ -2,13 +2,16 @@ package service_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/kukymbr/core2go/di"
 	"github.com/kukymbr/core2go/service"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 type panickingCmd struct{}
@@ -97,3 +100,43 @@ func TestService_Run_WhenRunnerPanicked_ExpectNoPanic(t *testing.T) {
 	})
 
 }
+
+func TestService_Run_WhenNamedRunnerFailed_ExpectNameInLogs(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	srv := service.New(&di.Container{}, zap.New(core))
+
+	srv.RegisterRunner(
+		service.Supervise(
+			service.Named("consumer", service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+				return errors.New("runner error")
+			})),
+			service.WithRestartPolicy(service.RestartNever),
+		),
+		service.NewRouterRunner(&service.NopRouter{}),
+	)
+
+	code := srv.Run(context.Background())
+
+	assert.Equal(t, service.DefaultExitCodes().RunnerFailed, code)
+
+	failed := logs.FilterField(zap.String("runner", "consumer")).FilterLevelExact(zap.ErrorLevel).All()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "failed to execute runner consumer: runner error", failed[0].Message)
+
+	vars := srv.Vars()
+	require.Len(t, vars.Runners, 2)
+	assert.Equal(t, "consumer", vars.Runners[0].Name)
+	assert.Equal(t, "#1", vars.Runners[1].Name)
+}
+
+func TestService_Run_WhenNamedRunnerPanicked_ExpectNameInPanicLog(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	srv := service.New(&di.Container{}, zap.New(core))
+
+	srv.RegisterRunner(service.Named("panicking", service.NewCommandRunner(&panickingCmd{})))
+	srv.Run(context.Background())
+
+	panics := logs.FilterField(zap.Bool("is_panic", true)).All()
+	require.Len(t, panics, 1)
+	assert.Equal(t, "panicking", panics[0].ContextMap()["runner"])
+}
@@ -0,0 +1,321 @@
+// Package servicetest provides a harness to test the service.Service deterministically:
+// the signals are sent to the fake channel instead of the process,
+// the logs are written to the observed logger and the container events are recorded.
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// DefaultTimeout is a default timeout of the Harness waiting methods.
+const DefaultTimeout = 5 * time.Second
+
+// Harness runs the service.Service in tests.
+type Harness struct {
+	// Service is the tested Service instance.
+	Service *service.Service
+
+	// Container is the Service's DI container.
+	Container *di.Container
+
+	// Logs are the Service logs.
+	Logs *observer.ObservedLogs
+
+	// Events are the recorded Container events.
+	Events *di.Recorder
+
+	// Timeout is a timeout of the waiting methods.
+	Timeout time.Duration
+
+	t       testing.TB
+	signals chan os.Signal
+	result  chan service.RunResult
+	cancel  context.CancelFunc
+
+	mu       sync.Mutex
+	count    int
+	started  []string
+	finished []string
+}
+
+// New creates the Harness with the Service using the given container
+// (or an empty one, if nil) and options.
+// The signal source is replaced with the fake channel, see Harness.Signal.
+func New(t testing.TB, ctn *di.Container, opts ...service.Option) *Harness {
+	t.Helper()
+
+	if ctn == nil {
+		ctn = &di.Container{}
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	signals := make(chan os.Signal, 1)
+	events := &di.Recorder{}
+
+	ctn.AddObserver(events)
+
+	opts = append(opts, service.WithSignalSource(signals))
+
+	return &Harness{
+		Service:   service.New(ctn, zap.New(core), opts...),
+		Container: ctn,
+		Logs:      logs,
+		Events:    events,
+		Timeout:   DefaultTimeout,
+		t:         t,
+		signals:   signals,
+		result:    make(chan service.RunResult, 1),
+	}
+}
+
+// Register registers the runners in the Service,
+// tracking the order of their starts and finishes.
+func (h *Harness) Register(runners ...service.Runner) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, runner := range runners {
+		tracked := &trackedRunner{Runner: runner, h: h}
+
+		h.Service.RegisterRunner(tracked)
+
+		// The name is assigned by the Service on the registration
+		registered := h.Service.Vars().Runners
+		tracked.name = registered[len(registered)-1].Name
+
+		h.count++
+	}
+}
+
+// Start runs the Service in the background.
+// The Service is stopped on the test cleanup if still running.
+func (h *Harness) Start(ctx context.Context) {
+	ctx, h.cancel = context.WithCancel(ctx)
+
+	h.t.Cleanup(func() {
+		h.cancel()
+
+		select {
+		case <-h.Service.Done():
+		case <-time.After(h.Timeout):
+			h.t.Errorf("service is not finished in %s after the test", h.Timeout)
+		}
+	})
+
+	go func() {
+		h.result <- h.Service.RunWithResult(ctx)
+	}()
+}
+
+// Run runs the Service and waits for its result.
+func (h *Harness) Run(ctx context.Context) service.RunResult {
+	h.t.Helper()
+
+	h.Start(ctx)
+
+	return h.Wait()
+}
+
+// WaitStarted waits for the runners with the given names to start.
+// If no names given, waits for all registered runners.
+func (h *Harness) WaitStarted(names ...string) {
+	h.t.Helper()
+
+	h.waitFor(func() bool {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if len(names) == 0 {
+			return len(h.started) == h.count
+		}
+
+		for _, name := range names {
+			if !contains(h.started, name) {
+				return false
+			}
+		}
+
+		return true
+	}, fmt.Sprintf("runners %v are not started", names))
+}
+
+// Signal sends the signal to the Service.
+func (h *Harness) Signal(sig os.Signal) {
+	h.t.Helper()
+
+	select {
+	case h.signals <- sig:
+	case <-time.After(h.Timeout):
+		h.t.Fatalf("signal %s is not received by the service in %s", sig, h.Timeout)
+	}
+}
+
+// Shutdown stops the Service with the given reason, see service.Service.Stop,
+// and waits for its result.
+func (h *Harness) Shutdown(reason string) service.RunResult {
+	h.t.Helper()
+
+	h.Service.Stop(reason)
+
+	return h.Wait()
+}
+
+// Wait waits for the Service to finish and returns its result.
+func (h *Harness) Wait() service.RunResult {
+	h.t.Helper()
+
+	select {
+	case result := <-h.result:
+		h.result <- result
+
+		return result
+	case <-time.After(h.Timeout):
+		h.t.Fatalf("service is not finished in %s", h.Timeout)
+
+		return service.RunResult{}
+	}
+}
+
+// Started returns names of the started runners in the order of their starts.
+func (h *Harness) Started() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]string(nil), h.started...)
+}
+
+// Finished returns names of the finished runners in the order of their finishes.
+func (h *Harness) Finished() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]string(nil), h.finished...)
+}
+
+// AssertExitCode checks the Service exit code, waiting for the Service to finish.
+func (h *Harness) AssertExitCode(expected int) bool {
+	h.t.Helper()
+
+	if code := h.Wait().ExitCode; code != expected {
+		h.t.Errorf("expected exit code %d, got %d", expected, code)
+
+		return false
+	}
+
+	return true
+}
+
+// AssertStartOrder checks the runners started in the given order.
+func (h *Harness) AssertStartOrder(names ...string) bool {
+	h.t.Helper()
+
+	return h.assertOrder("start", h.Started(), names)
+}
+
+// AssertFinishOrder checks the runners finished in the given order.
+func (h *Harness) AssertFinishOrder(names ...string) bool {
+	h.t.Helper()
+
+	return h.assertOrder("finish", h.Finished(), names)
+}
+
+// AssertContainerClosed checks the Container is closed exactly once.
+func (h *Harness) AssertContainerClosed() bool {
+	h.t.Helper()
+
+	if count := h.Events.Count(di.EventCloseFinish, ""); count != 1 {
+		h.t.Errorf("expected container to be closed once, closed %d times", count)
+
+		return false
+	}
+
+	return true
+}
+
+// AssertContainerNotClosed checks the Container is not closed.
+func (h *Harness) AssertContainerNotClosed() bool {
+	h.t.Helper()
+
+	if count := h.Events.Count(di.EventCloseStart, ""); count != 0 {
+		h.t.Errorf("expected container not to be closed, closed %d times", count)
+
+		return false
+	}
+
+	return true
+}
+
+func (h *Harness) assertOrder(kind string, actual []string, expected []string) bool {
+	h.t.Helper()
+
+	if !reflect.DeepEqual(actual, expected) {
+		h.t.Errorf("expected runners %s order %v, got %v", kind, expected, actual)
+
+		return false
+	}
+
+	return true
+}
+
+func (h *Harness) waitFor(condition func() bool, msg string) {
+	h.t.Helper()
+
+	deadline := time.Now().Add(h.Timeout)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			h.t.Fatalf("%s in %s", msg, h.Timeout)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// trackedRunner is a Runner recording its start and finish in the Harness.
+type trackedRunner struct {
+	service.Runner
+
+	name string
+	h    *Harness
+}
+
+func (r *trackedRunner) Run(ctx context.Context, ctn *di.Container) error {
+	r.h.mu.Lock()
+	r.h.started = append(r.h.started, r.name)
+	r.h.mu.Unlock()
+
+	defer func() {
+		r.h.mu.Lock()
+		r.h.finished = append(r.h.finished, r.name)
+		r.h.mu.Unlock()
+	}()
+
+	return r.Runner.Run(ctx, ctn)
+}
+
+// Unwrap returns the tracked Runner.
+func (r *trackedRunner) Unwrap() service.Runner {
+	return r.Runner
+}
+
+func contains(list []string, item string) bool {
+	for _, v := range list {
+		if v == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package servicetest_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"github.com/kukymbr/core2go/service/servicetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func waitingRunner(name string) service.Runner {
+	return service.Named(name, service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+		<-ctx.Done()
+
+		return nil
+	}))
+}
+
+func TestHarness_WhenSignaled_ExpectSignaledExitCode(t *testing.T) {
+	h := servicetest.New(t, nil)
+
+	h.Register(waitingRunner("first"), waitingRunner("second"))
+	h.Start(context.Background())
+	h.WaitStarted()
+	h.Signal(syscall.SIGTERM)
+
+	h.AssertExitCode(service.DefaultExitCodes().Signaled)
+	h.AssertContainerClosed()
+
+	result := h.Wait()
+
+	assert.Equal(t, service.TriggerSignal, result.Trigger)
+	assert.ElementsMatch(t, []string{"first", "second"}, h.Finished())
+	assert.Equal(t, 1, h.Logs.FilterMessage("Got shutdown signal: terminated").Len())
+}
+
+func TestHarness_WhenRunnerFailed_ExpectFinishOrder(t *testing.T) {
+	h := servicetest.New(t, nil)
+	started := make(chan struct{})
+
+	h.Register(
+		service.Named("failing", service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+			<-started
+
+			return errors.New("runner error")
+		})),
+		service.Named("waiting", service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+			close(started)
+			<-ctx.Done()
+
+			return nil
+		})),
+	)
+
+	result := h.Run(context.Background())
+
+	assert.Equal(t, service.TriggerRunnerFailed, result.Trigger)
+	h.AssertExitCode(service.DefaultExitCodes().RunnerFailed)
+	h.AssertFinishOrder("failing", "waiting")
+	h.AssertContainerClosed()
+}
+
+func TestHarness_Shutdown(t *testing.T) {
+	h := servicetest.New(t, nil)
+
+	h.Register(waitingRunner("only"))
+	h.Start(context.Background())
+	h.WaitStarted("only")
+
+	result := h.Shutdown("test")
+
+	assert.Equal(t, service.TriggerStopped, result.Trigger)
+	assert.Equal(t, "test", result.Reason)
+	h.AssertStartOrder("only")
+	h.AssertContainerClosed()
+}
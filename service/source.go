@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrSourceClosed is returned by the Source when no more jobs will be fetched.
+var ErrSourceClosed = errors.New("source is closed")
+
+// Source is a source of the jobs processed by the worker pool Runner, see NewWorkerPoolRunner.
+type Source interface {
+	// Fetch returns the next job, blocking until it's available or the context is canceled.
+	// Returns ErrSourceClosed when no more jobs will be fetched.
+	Fetch(ctx context.Context) (Job, error)
+}
+
+// Job is a job fetched from the Source.
+type Job interface {
+	// ID returns the job identifier.
+	ID() string
+
+	// Payload returns the job data.
+	Payload() any
+
+	// Ack acknowledges the job is processed.
+	Ack(ctx context.Context) error
+
+	// Nack reports the job is not processed.
+	// If requeue is true, the job should be fetched again.
+	Nack(ctx context.Context, requeue bool) error
+}
+
+// NewChanSource creates new in-memory channel-based Source with the given buffer size.
+func NewChanSource(size int) *ChanSource {
+	return &ChanSource{
+		jobs:   make(chan *chanJob, size),
+		closed: make(chan struct{}),
+	}
+}
+
+// ChanSource is an in-memory channel-based Source.
+// Acknowledged and dead jobs are dropped, requeued jobs are pushed back to the channel.
+type ChanSource struct {
+	jobs      chan *chanJob
+	closed    chan struct{}
+	closeOnce sync.Once
+	seq       atomic.Int64
+}
+
+// Push adds the job with the payload to the Source, blocking until it's buffered or the context is canceled.
+// Returns the job ID.
+func (s *ChanSource) Push(ctx context.Context, payload any) (string, error) {
+	job := &chanJob{
+		id:      strconv.FormatInt(s.seq.Add(1), 10),
+		payload: payload,
+		source:  s,
+	}
+
+	select {
+	case <-s.closed:
+		return "", ErrSourceClosed
+	default:
+	}
+
+	select {
+	case s.jobs <- job:
+		return job.id, nil
+	case <-s.closed:
+		return "", ErrSourceClosed
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Close closes the Source: the buffered jobs are still fetched,
+// then the ErrSourceClosed is returned.
+func (s *ChanSource) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+}
+
+// Len returns count of the buffered jobs.
+func (s *ChanSource) Len() int {
+	return len(s.jobs)
+}
+
+func (s *ChanSource) Fetch(ctx context.Context) (Job, error) {
+	select {
+	case job := <-s.jobs:
+		return job, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.closed:
+	}
+
+	select {
+	case job := <-s.jobs:
+		return job, nil
+	default:
+		return nil, ErrSourceClosed
+	}
+}
+
+// requeue pushes the job back to the channel without blocking the caller.
+func (s *ChanSource) requeue(job *chanJob) {
+	select {
+	case s.jobs <- job:
+		return
+	default:
+	}
+
+	go func() {
+		select {
+		case s.jobs <- job:
+		case <-s.closed:
+		}
+	}()
+}
+
+type chanJob struct {
+	id      string
+	payload any
+	source  *ChanSource
+}
+
+func (j *chanJob) ID() string {
+	return j.id
+}
+
+func (j *chanJob) Payload() any {
+	return j.payload
+}
+
+func (j *chanJob) Ack(_ context.Context) error {
+	return nil
+}
+
+func (j *chanJob) Nack(_ context.Context, requeue bool) error {
+	if requeue {
+		j.source.requeue(j)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"go.uber.org/zap"
+)
+
+// StagedRunner is a Runner started at the startup stage.
+// Runners not implementing it are started at the stage 0.
+//
+// Stages are started in ascending order: the stage is started when all runners of the previous one
+// reported ready (see Ready) or returned, or the stage timeout elapsed (see WithStageTimeout).
+// The runner of the stage must call Ready, otherwise the later stages are delayed
+// by the whole stage timeout (DefaultStageTimeout by default, forever if zero).
+// Built-in runners report ready themselves when started (the HTTP server runner when the listener is bound).
+// On the shutdown, stages are stopped in the reverse order:
+// the runners of the stage are canceled when all runners of the next stages are returned.
+type StagedRunner interface {
+	Runner
+
+	// Stage returns the Runner's startup stage.
+	Stage() int
+}
+
+// InStage returns the Runner started at the given stage, see StagedRunner.
+// The later stages wait for the Runner to call Ready (or return), custom runners must call it
+// as soon as they are ready to serve. Built-in runners call Ready themselves.
+func InStage(stage int, runner Runner) StagedRunner {
+	return &stagedRunner{stage: stage, runner: runner}
+}
+
+type stagedRunner struct {
+	stage  int
+	runner Runner
+}
+
+func (r *stagedRunner) Run(ctx context.Context, ctn *di.Container) error {
+	return r.runner.Run(ctx, ctn)
+}
+
+// Stage returns the Runner's startup stage.
+func (r *stagedRunner) Stage() int {
+	return r.stage
+}
+
+// Unwrap returns the staged Runner.
+func (r *stagedRunner) Unwrap() Runner {
+	return r.runner
+}
+
+// stageOf returns the runner's stage if it's a StagedRunner or wraps one, 0 otherwise.
+func stageOf(runner Runner) int {
+	if r, ok := findRunner[StagedRunner](runner); ok {
+		return r.Stage()
+	}
+
+	return 0
+}
+
+// stage is a group of the runners started together
+type stage struct {
+	num     int
+	runners []int
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	ready   []chan struct{}
+}
+
+// stages returns the registered runners grouped by stages in the start order.
+// Stages contexts keep values of the given context, but are canceled separately.
+func (s *Service) stages(ctx context.Context) []*stage {
+	byNum := make(map[int]*stage)
+	stages := make([]*stage, 0)
+
+	for i, state := range s.states {
+		st, ok := byNum[state.stage]
+		if !ok {
+			st = &stage{num: state.stage}
+			st.ctx, st.cancel = context.WithCancel(detachedContext{parent: ctx})
+
+			byNum[state.stage] = st
+			stages = append(stages, st)
+		}
+
+		st.runners = append(st.runners, i)
+		st.ready = append(st.ready, make(chan struct{}))
+	}
+
+	sort.Slice(stages, func(i, j int) bool {
+		return stages[i].num < stages[j].num
+	})
+
+	return stages
+}
+
+// waitReady waits for the runners of the stage to become ready.
+func (s *Service) waitReady(ctx context.Context, st *stage) {
+	var deadline <-chan time.Time
+
+	if s.opt.stageTimeout > 0 {
+		timer := time.NewTimer(s.opt.stageTimeout)
+		defer timer.Stop()
+
+		deadline = timer.C
+	}
+
+	var tick <-chan time.Time
+
+	if s.opt.stageLogInterval > 0 {
+		ticker := time.NewTicker(s.opt.stageLogInterval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	for i := 0; i < len(st.ready); {
+		select {
+		case <-st.ready[i]:
+			i++
+		case <-ctx.Done():
+			return
+		case <-tick:
+			s.log.Info(
+				fmt.Sprintf("Waiting for the stage %d runners to become ready", st.num),
+				zap.Strings("not_ready_runners", s.notReadyRunners(st, i)),
+			)
+		case <-deadline:
+			s.log.Warn(
+				fmt.Sprintf("Stage %d readiness timeout exceeded, starting the next stage", st.num),
+				zap.Strings("not_ready_runners", s.notReadyRunners(st, i)),
+			)
+
+			return
+		}
+	}
+
+	s.log.Debug(fmt.Sprintf("Stage %d is ready", st.num))
+}
+
+// notReadyRunners returns names of the stage runners not ready yet, starting from the given one.
+func (s *Service) notReadyRunners(st *stage, from int) []string {
+	names := make([]string, 0)
+
+	for i := from; i < len(st.ready); i++ {
+		select {
+		case <-st.ready[i]:
+		default:
+			names = append(names, s.runnerName(st.runners[i]))
+		}
+	}
+
+	return names
+}
+
+// stopStages cancels the stages in reverse order after the Service context is canceled,
+// waiting for the runners of each stage to return before canceling the previous one.
+func stopStages(ctx context.Context, stages []*stage) {
+	<-ctx.Done()
+
+	for i := len(stages) - 1; i >= 0; i-- {
+		stages[i].cancel()
+		stages[i].wg.Wait()
+	}
+}
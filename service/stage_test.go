@@ -0,0 +1,155 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"github.com/kukymbr/core2go/service/servicetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func readyRunner(delay time.Duration) service.Runner {
+	return service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+		time.Sleep(delay)
+		service.Ready(ctx)
+
+		<-ctx.Done()
+
+		return nil
+	})
+}
+
+func TestService_Run_WhenStages_ExpectStartedAndStoppedInOrder(t *testing.T) {
+	h := servicetest.New(t, nil)
+
+	h.Register(
+		service.Named("http", service.InStage(2, readyRunner(0))),
+		service.Named("cache", service.InStage(1, readyRunner(20*time.Millisecond))),
+		service.Named("db", readyRunner(10*time.Millisecond)),
+	)
+	h.Start(context.Background())
+	h.WaitStarted()
+
+	result := h.Shutdown("test")
+
+	assert.Equal(t, service.TriggerStopped, result.Trigger)
+	h.AssertStartOrder("db", "cache", "http")
+	h.AssertFinishOrder("http", "cache", "db")
+}
+
+func TestService_Run_WhenTaskReturned_ExpectNextStageStarted(t *testing.T) {
+	h := servicetest.New(t, nil)
+
+	h.Register(
+		service.Named("migration", service.Task(service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+			time.Sleep(10 * time.Millisecond)
+
+			return nil
+		}))),
+		service.Named("http", service.InStage(1, readyRunner(0))),
+	)
+	h.Start(context.Background())
+	h.WaitStarted()
+
+	h.AssertFinishOrder("migration")
+	h.Shutdown("test")
+}
+
+func TestService_Run_WhenStageTimeout_ExpectNextStageStarted(t *testing.T) {
+	h := servicetest.New(t, nil, service.WithStageTimeout(20*time.Millisecond))
+
+	h.Register(
+		service.Named("never-ready", service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+			<-ctx.Done()
+
+			return nil
+		})),
+		service.Named("http", service.InStage(1, readyRunner(0))),
+	)
+	h.Start(context.Background())
+	h.WaitStarted()
+
+	h.AssertStartOrder("never-ready", "http")
+	h.Shutdown("test")
+
+	entries := h.Logs.FilterMessage("Stage 0 readiness timeout exceeded, starting the next stage").All()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, []any{"never-ready"}, entries[0].ContextMap()["not_ready_runners"])
+	}
+}
+
+func TestService_Run_WhenBuiltInRunnersInStage_ExpectNextStageStartedWithoutTimeout(t *testing.T) {
+	h := servicetest.New(t, nil)
+	h.Timeout = time.Second
+
+	h.Register(
+		service.Named("router", service.NewRouterRunner(&service.NopRouter{})),
+		service.Named("command", service.InStage(1, service.NewCommandRunner(blockingCommand{}))),
+		service.Named("http", service.InStage(2, readyRunner(0))),
+	)
+	h.Start(context.Background())
+	h.WaitStarted()
+
+	h.AssertStartOrder("router", "command", "http")
+	h.Shutdown("test")
+
+	assert.Empty(t, h.Logs.FilterMessageSnippet("readiness timeout exceeded").All())
+}
+
+func TestService_Run_WhenDefaultStageTimeout_ExpectNextStageWaiting(t *testing.T) {
+	h := servicetest.New(t, nil, service.WithStageLogInterval(5*time.Millisecond))
+
+	h.Register(
+		service.Named("never-ready", service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+			<-ctx.Done()
+
+			return nil
+		})),
+		service.Named("http", service.InStage(1, readyRunner(0))),
+	)
+	h.Start(context.Background())
+	h.WaitStarted("never-ready")
+
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, time.Minute, service.DefaultStageTimeout)
+	assert.Equal(t, []string{"never-ready"}, h.Started())
+
+	h.Shutdown("test")
+
+	entries := h.Logs.FilterMessage("Waiting for the stage 0 runners to become ready").All()
+	if assert.NotEmpty(t, entries) {
+		assert.Equal(t, []any{"never-ready"}, entries[0].ContextMap()["not_ready_runners"])
+	}
+}
+
+func TestService_Run_WhenStageLogIntervalZero_ExpectNoPeriodicLogs(t *testing.T) {
+	h := servicetest.New(t, nil, service.WithStageTimeout(30*time.Millisecond), service.WithStageLogInterval(0))
+
+	h.Register(
+		service.Named("never-ready", service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+			<-ctx.Done()
+
+			return nil
+		})),
+		service.Named("http", service.InStage(1, readyRunner(0))),
+	)
+	h.Start(context.Background())
+	h.WaitStarted()
+	h.Shutdown("test")
+
+	assert.Empty(t, h.Logs.FilterMessage("Waiting for the stage 0 runners to become ready").All())
+	assert.Len(t, h.Logs.FilterMessage("Stage 0 readiness timeout exceeded, starting the next stage").All(), 1)
+}
+
+// blockingCommand is a Command running until the context is canceled
+type blockingCommand struct{}
+
+func (blockingCommand) ExecuteContext(ctx context.Context) error {
+	<-ctx.Done()
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/logtools"
+	"go.uber.org/zap"
+)
+
+// ErrMaxRestartsExceeded is returned by the supervised Runner
+// when it's restarted too many times within the restarts window.
+var ErrMaxRestartsExceeded = errors.New("max restarts exceeded")
+
+// RestartPolicy defines when the supervised Runner is restarted.
+type RestartPolicy int
+
+// Restart policies
+const (
+	// RestartOnFailure restarts the Runner if it returned an error or panicked.
+	RestartOnFailure RestartPolicy = iota
+
+	// RestartAlways restarts the Runner whenever it returned.
+	RestartAlways
+
+	// RestartNever doesn't restart the Runner.
+	RestartNever
+)
+
+// Default supervisor options.
+const (
+	DefaultBackoffInitial = 100 * time.Millisecond
+	DefaultBackoffMax     = 30 * time.Second
+	DefaultMaxRestarts    = 5
+	DefaultRestartsWindow = time.Minute
+)
+
+// SupervisorOption is an option of the supervised Runner.
+type SupervisorOption func(s *supervisor)
+
+// WithRestartPolicy sets the restart policy, default is RestartOnFailure.
+func WithRestartPolicy(policy RestartPolicy) SupervisorOption {
+	return func(s *supervisor) {
+		s.policy = policy
+	}
+}
+
+// WithBackoff sets the exponential backoff between restarts:
+// the delay starts from the initial value and doubles with each restart within the window up to maxDelay.
+// Zero maxDelay means no cap.
+func WithBackoff(initial time.Duration, maxDelay time.Duration) SupervisorOption {
+	return func(s *supervisor) {
+		s.backoffInitial = initial
+		s.backoffMax = maxDelay
+	}
+}
+
+// WithMaxRestarts sets the maximum restarts count within the time window.
+// When exceeded, the supervised Runner returns the ErrMaxRestartsExceeded error.
+// Zero count means unlimited restarts.
+func WithMaxRestarts(count int, window time.Duration) SupervisorOption {
+	return func(s *supervisor) {
+		s.maxRestarts = count
+		s.window = window
+	}
+}
+
+// WithPanicAsFailure sets if the Runner's panic is recovered and treated as a failure (default).
+// If false, the panic is passed to the Service, which stops.
+func WithPanicAsFailure(enabled bool) SupervisorOption {
+	return func(s *supervisor) {
+		s.panicAsFailure = enabled
+	}
+}
+
+// Supervise returns the Runner restarting the given one according to the restart policy.
+// Runner is not restarted when the context is canceled.
+// Restarts are logged with the logger from the context, see LoggerFromContext.
+func Supervise(runner Runner, opts ...SupervisorOption) Runner {
+	s := &supervisor{
+		runner:         runner,
+		policy:         RestartOnFailure,
+		backoffInitial: DefaultBackoffInitial,
+		backoffMax:     DefaultBackoffMax,
+		maxRestarts:    DefaultMaxRestarts,
+		window:         DefaultRestartsWindow,
+		panicAsFailure: true,
+	}
+
+	for _, fn := range opts {
+		fn(s)
+	}
+
+	return s
+}
+
+type supervisor struct {
+	runner         Runner
+	policy         RestartPolicy
+	backoffInitial time.Duration
+	backoffMax     time.Duration
+	maxRestarts    int
+	window         time.Duration
+	panicAsFailure bool
+}
+
+func (s *supervisor) Run(ctx context.Context, ctn *di.Container) error {
+	log := LoggerFromContext(ctx)
+	restarts := make([]time.Time, 0)
+
+	for {
+		err := s.runOnce(ctx, log, ctn)
+
+		if ctx.Err() != nil || !s.shouldRestart(err) {
+			return err
+		}
+
+		restarts = s.trimRestarts(restarts, time.Now())
+
+		if s.maxRestarts > 0 && len(restarts) >= s.maxRestarts {
+			log.Error("Runner is restarted too many times, giving up", zap.Int("restarts", len(restarts)))
+
+			return errors.Join(fmt.Errorf("%w: %d within %s", ErrMaxRestartsExceeded, len(restarts), s.window), err)
+		}
+
+		delay := s.backoff(len(restarts))
+
+		log.Warn(
+			"Restarting the runner",
+			zap.Error(err),
+			zap.Int("restart", len(restarts)+1),
+			zap.Duration("delay", delay),
+		)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return err
+		case <-timer.C:
+		}
+
+		restarts = append(restarts, time.Now())
+	}
+}
+
+// Unwrap returns the supervised Runner.
+func (s *supervisor) Unwrap() Runner {
+	return s.runner
+}
+
+// runOnce runs the Runner, converts panic to an error if enabled.
+func (s *supervisor) runOnce(ctx context.Context, log *zap.Logger, ctn *di.Container) (err error) {
+	if !s.panicAsFailure {
+		return s.runner.Run(ctx, ctn)
+	}
+
+	defer logtools.CatchPanic(log, func(recovered any) {
+		err = newPanicError("", recovered)
+	})
+
+	return s.runner.Run(ctx, ctn)
+}
+
+// shouldRestart checks if the Runner should be restarted after it returned the error.
+func (s *supervisor) shouldRestart(err error) bool {
+	switch s.policy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+// trimRestarts removes restarts outside the window.
+func (s *supervisor) trimRestarts(restarts []time.Time, now time.Time) []time.Time {
+	if s.window <= 0 {
+		return restarts
+	}
+
+	for len(restarts) > 0 && now.Sub(restarts[0]) > s.window {
+		restarts = restarts[1:]
+	}
+
+	return restarts
+}
+
+// backoff returns the delay before the next restart.
+func (s *supervisor) backoff(restarts int) time.Duration {
+	delay := s.backoffInitial
+
+	for i := 0; i < restarts && delay < math.MaxInt64/2; i++ {
+		if s.backoffMax > 0 && delay >= s.backoffMax {
+			break
+		}
+
+		delay *= 2
+	}
+
+	if s.backoffMax > 0 && delay > s.backoffMax {
+		delay = s.backoffMax
+	}
+
+	return delay
+}
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+	"github.com/kukymbr/core2go/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSupervise_WhenFailing_ExpectRestartedUntilMax(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	srv := service.New(&di.Container{}, zap.New(core))
+	runs := atomic.Int32{}
+
+	srv.RegisterRunner(service.Supervise(
+		service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+			if runs.Add(1) == 2 {
+				panic("test panic")
+			}
+
+			return errors.New("runner error")
+		}),
+		service.WithBackoff(time.Millisecond, 5*time.Millisecond),
+		service.WithMaxRestarts(3, time.Minute),
+	))
+
+	code := srv.Run(context.Background())
+
+	assert.Equal(t, service.DefaultExitCodes().RunnerFailed, code)
+	assert.Equal(t, int32(4), runs.Load())
+
+	restarts := logs.FilterMessage("Restarting the runner").All()
+	require.Len(t, restarts, 3)
+	assert.Equal(t, "#0", restarts[0].ContextMap()["runner"])
+}
+
+func TestSupervise_WhenPolicy_ExpectRestartedByPolicy(t *testing.T) {
+	tests := []struct {
+		Policy       service.RestartPolicy
+		Err          error
+		ExpectedRuns int32
+	}{
+		{service.RestartOnFailure, nil, 1},
+		{service.RestartOnFailure, errors.New("runner error"), 3},
+		{service.RestartAlways, nil, 3},
+		{service.RestartNever, errors.New("runner error"), 1},
+	}
+
+	for i, test := range tests {
+		runs := atomic.Int32{}
+		err := test.Err
+
+		runner := service.Supervise(
+			service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+				runs.Add(1)
+
+				return err
+			}),
+			service.WithRestartPolicy(test.Policy),
+			service.WithBackoff(0, 0),
+			service.WithMaxRestarts(2, time.Minute),
+		)
+
+		_ = runner.Run(context.Background(), nil)
+
+		assert.Equal(t, test.ExpectedRuns, runs.Load(), i)
+	}
+}
+
+func TestSupervise_WhenCanceled_ExpectNotRestarted(t *testing.T) {
+	runs := atomic.Int32{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runner := service.Supervise(
+		service.NewCustomRunner(func(ctx context.Context, _ *di.Container) error {
+			runs.Add(1)
+			cancel()
+
+			return ctx.Err()
+		}),
+		service.WithRestartPolicy(service.RestartAlways),
+	)
+
+	err := runner.Run(ctx, nil)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), runs.Load())
+}
+
+func TestSupervise_WhenPanicNotFailure_ExpectPanic(t *testing.T) {
+	runner := service.Supervise(
+		service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+			panic("test panic")
+		}),
+		service.WithPanicAsFailure(false),
+	)
+
+	assert.Panics(t, func() {
+		_ = runner.Run(context.Background(), nil)
+	})
+}
+
+func TestSupervise_WhenPanickedWithUncappedBackoff_ExpectPanicResult(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	srv := service.New(&di.Container{}, zap.New(core))
+
+	srv.RegisterRunner(service.Supervise(
+		service.NewCustomRunner(func(_ context.Context, _ *di.Container) error {
+			panic("test panic")
+		}),
+		service.WithBackoff(time.Millisecond, 0),
+		service.WithMaxRestarts(3, time.Minute),
+	))
+
+	result := srv.RunWithResult(context.Background())
+
+	require.Len(t, result.Runners, 1)
+	assert.Equal(t, "test panic", result.Runners[0].Panic)
+	assert.Contains(t, string(result.Runners[0].PanicStack), "supervisor_test.go")
+	assert.ErrorIs(t, result.Runners[0].Err, service.ErrMaxRestartsExceeded)
+
+	restarts := logs.FilterMessage("Restarting the runner").All()
+	require.Len(t, restarts, 3)
+
+	for i, delay := range []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond} {
+		assert.Equal(t, delay, restarts[i].ContextMap()["delay"])
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kukymbr/core2go/di"
+)
+
+// RunnerStatus is a status of the Runner execution
+type RunnerStatus int
+
+// Runner statuses
+const (
+	RunnerRegistered RunnerStatus = iota
+	RunnerRunning
+	RunnerDone
+	RunnerFailed
+	RunnerCanceled
+)
+
+// String returns the status name
+func (s RunnerStatus) String() string {
+	switch s {
+	case RunnerRegistered:
+		return "registered"
+	case RunnerRunning:
+		return "running"
+	case RunnerDone:
+		return "done"
+	case RunnerFailed:
+		return "failed"
+	case RunnerCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// RunnerVars is a Runner state exposed by the Service.Vars
+type RunnerVars struct {
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	Stage  int    `json:"stage"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Vars is a Service state exposed by the Service.Vars
+type Vars struct {
+	Running        bool              `json:"running"`
+	ExitCode       int               `json:"exit_code"`
+	Reloads        int               `json:"reloads"`
+	ReloadFailures int               `json:"reload_failures"`
+	Runners        []RunnerVars      `json:"runners"`
+	Container      *di.ContainerVars `json:"container,omitempty"`
+}
+
+// Vars returns the Service state.
+// Safe to call concurrently with the RegisterRunner and the Run.
+func (s *Service) Vars() Vars {
+	states := s.registeredStates()
+
+	vars := Vars{
+		Running:        s.running.Load(),
+		ExitCode:       int(s.exitCode.Load()),
+		Reloads:        int(s.reloads.Load()),
+		ReloadFailures: int(s.reloadFailures.Load()),
+		Runners:        make([]RunnerVars, 0, len(states)),
+	}
+
+	for _, state := range states {
+		status, err := state.get()
+
+		rv := RunnerVars{
+			Name:   state.name,
+			Role:   state.role.String(),
+			Stage:  state.stage,
+			Status: status.String(),
+		}
+
+		if err != nil {
+			rv.Error = err.Error()
+		}
+
+		vars.Runners = append(vars.Runners, rv)
+	}
+
+	if s.ctn != nil {
+		ctnVars := s.ctn.Vars()
+		vars.Container = &ctnVars
+	}
+
+	return vars
+}
+
+// runnerState is a Runner execution state
+type runnerState struct {
+	name  string
+	role  Role
+	stage int
+
+	mu       sync.Mutex
+	status   RunnerStatus
+	err      error
+	started  time.Time
+	duration time.Duration
+	canceled bool
+}
+
+// start marks the Runner as running
+func (r *runnerState) start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status = RunnerRunning
+	r.started = time.Now()
+}
+
+// finish sets the Runner's final status
+func (r *runnerState) finish(status RunnerStatus, err error, canceled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status = status
+	r.err = err
+	r.canceled = canceled
+
+	if !r.started.IsZero() {
+		r.duration = time.Since(r.started)
+	}
+}
+
+func (r *runnerState) get() (RunnerStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.status, r.err
+}
+
+// result returns the Runner execution outcome
+func (r *runnerState) result() RunnerResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := RunnerResult{
+		Name:     r.name,
+		Role:     r.role,
+		Status:   r.status,
+		Err:      r.err,
+		Duration: r.duration,
+		Canceled: r.canceled,
+	}
+
+	if r.status == RunnerRunning {
+		result.Duration = time.Since(r.started)
+	}
+
+	var panicked *panicError
+	if errors.As(r.err, &panicked) {
+		result.Panic = panicked.value
+		result.PanicStack = panicked.stack
+	}
+
+	return result
+}
@@ -0,0 +1,59 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/kukymbr/core2go/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Vars(t *testing.T) {
+	srv := getSrv()
+
+	srv.RegisterRunner(service.NewCommandRunner(&service.NopCommand{}), service.NewCommandRunner(&panickingCmd{}))
+
+	vars := srv.Vars()
+
+	require.Len(t, vars.Runners, 2)
+	assert.Equal(t, "registered", vars.Runners[0].Status)
+	assert.NotNil(t, vars.Container)
+
+	code := srv.Run(context.Background())
+
+	vars = srv.Vars()
+
+	assert.False(t, vars.Running)
+	assert.Equal(t, code, vars.ExitCode)
+	assert.Equal(t, service.RunnerFailed.String(), vars.Runners[1].Status)
+	assert.NotEmpty(t, vars.Runners[1].Error)
+}
+
+func TestService_Vars_WhenRegisteringConcurrently_ExpectNoRace(t *testing.T) {
+	srv := getSrv()
+	wg := sync.WaitGroup{}
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			srv.RegisterRunner(service.NewCommandRunner(&service.NopCommand{}))
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			_ = srv.Vars()
+		}
+	}()
+
+	wg.Wait()
+
+	assert.Len(t, srv.Vars().Runners, 100)
+}